@@ -0,0 +1,199 @@
+package cerb
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Address represents an email address known to Cerb. Every sender and recipient gets an address record which may be linked to a contact and org. @see https://cerb.ai/docs/records/types/address/
+type Address struct {
+	ID         int    `json:"id"`
+	Email      string `json:"email"`
+	ContactID  int    `json:"contact_id"`
+	OrgID      int    `json:"org_id"`
+	IsBanned   int    `json:"is_banned"`
+	IsDefunct  int    `json:"is_defunct"`
+	NumSpam    int    `json:"num_spam"`
+	NumNonSpam int    `json:"num_nonspam"`
+	URL        string `json:"record_url"`
+	Updated    int    `json:"updated"`
+}
+
+// SearchAddressesResponse is the response from the records/address/search.json endpoint
+type SearchAddressesResponse struct {
+	Status  string    `json:"__status"`
+	Count   int       `json:"count"`
+	Limit   int       `json:"limit"`
+	Page    int       `json:"page"`
+	Results []Address `json:"results"`
+	Total   int       `json:"total"`
+	Version string    `json:"__version"`
+}
+
+// Sender bundles an address with the contact and org it belongs to. Contact and Org are nil when the address isn't linked to one.
+type Sender struct {
+	Address Address
+	Contact *Contact
+	Org     *Org
+}
+
+// SearchAddresses finds addresses matching the given Cerb search query, e.g. `isBanned:y`
+func (c Cerberus) SearchAddresses(query string, opts ...SearchOptions) (*[]Address, error) {
+	limit := 250 // If you need pagination imitate ListOpenTickets
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(limit))
+	applySearchOptions(params, opts)
+
+	var r SearchAddressesResponse
+	err := c.performRequest(http.MethodGet, "records/address/search.json", params, nil, &r)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to search addresses: %v", err)
+	}
+
+	return &r.Results, nil
+}
+
+// GetAddress loads the address with the given ID
+func (c Cerberus) GetAddress(addressID int) (*Address, error) {
+	var a Address
+	err := c.performRequest(http.MethodGet, "records/address/"+strconv.Itoa(addressID)+".json", nil, nil, &a)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get address %d: %v", addressID, err)
+	}
+
+	return &a, nil
+}
+
+// FindAddressByEmail finds the address record for the given email. Returns ErrNotFound when Cerb hasn't seen the address.
+func (c Cerberus) FindAddressByEmail(email string) (*Address, error) {
+	addresses, err := c.SearchAddresses(`email:"` + email + `"`)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range *addresses {
+		if strings.EqualFold(a.Email, email) {
+			return &a, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// FindOrCreateAddress finds the address record for the given email, creating it if Cerb hasn't seen it before
+func (c Cerberus) FindOrCreateAddress(email string) (*Address, error) {
+	a, err := c.FindAddressByEmail(email)
+
+	if err == nil {
+		return a, nil
+	}
+	if err != ErrNotFound {
+		return nil, fmt.Errorf("Failed to search for address %s: %v", email, err)
+	}
+
+	form := url.Values{}
+	form.Set("fields[email]", email)
+
+	var created Address
+	err = c.performRequest(http.MethodPost, "records/address/create.json", nil, form, &created)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create address %s: %v", email, err)
+	}
+
+	return &created, nil
+}
+
+// UpdateAddress saves the contact, org, banned and defunct fields of a. Load the address with GetAddress first so the fields you don't intend to change keep their values.
+func (c Cerberus) UpdateAddress(a Address) (*Address, error) {
+	form := url.Values{}
+	form.Set("fields[contact_id]", strconv.Itoa(a.ContactID))
+	form.Set("fields[org_id]", strconv.Itoa(a.OrgID))
+	form.Set("fields[is_banned]", strconv.Itoa(a.IsBanned))
+	form.Set("fields[is_defunct]", strconv.Itoa(a.IsDefunct))
+
+	return c.updateAddress(a.ID, form)
+}
+
+// SetAddressContact links the address to the given contact
+func (c Cerberus) SetAddressContact(addressID int, contactID int) error {
+	form := url.Values{}
+	form.Set("fields[contact_id]", strconv.Itoa(contactID))
+
+	_, err := c.updateAddress(addressID, form)
+	return err
+}
+
+// SetAddressBanned bans (or unbans) an address. Cerb rejects new mail from banned addresses.
+func (c Cerberus) SetAddressBanned(addressID int, banned bool) error {
+	form := url.Values{}
+	form.Set("fields[is_banned]", boolFlag(banned))
+
+	_, err := c.updateAddress(addressID, form)
+	return err
+}
+
+// SetAddressDefunct marks an address as defunct (or not). Cerb won't send mail to defunct addresses.
+func (c Cerberus) SetAddressDefunct(addressID int, defunct bool) error {
+	form := url.Values{}
+	form.Set("fields[is_defunct]", boolFlag(defunct))
+
+	_, err := c.updateAddress(addressID, form)
+	return err
+}
+
+func (c Cerberus) updateAddress(addressID int, form url.Values) (*Address, error) {
+	var updated Address
+	err := c.performRequest(http.MethodPut, "records/address/"+strconv.Itoa(addressID)+".json", nil, form, &updated)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update address %d: %v", addressID, err)
+	}
+
+	return &updated, nil
+}
+
+// LookupSender finds the address, contact and org for the given sender email. Returns ErrNotFound when Cerb hasn't seen the address.
+func (c Cerberus) LookupSender(email string) (*Sender, error) {
+	a, err := c.FindAddressByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	s := Sender{Address: *a}
+
+	if a.ContactID != 0 {
+		s.Contact, err = c.GetContact(a.ContactID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	orgID := a.OrgID
+	if orgID == 0 && s.Contact != nil {
+		orgID = s.Contact.OrgID
+	}
+	if orgID != 0 {
+		s.Org, err = c.GetOrg(orgID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &s, nil
+}
+
+// boolFlag converts b to the "1"/"0" Cerb uses for boolean fields
+func boolFlag(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
@@ -0,0 +1,46 @@
+package cerb
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Attachment represents a file stored in Cerb. @see https://cerb.ai/docs/records/types/attachment/
+type Attachment struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	MimeType string `json:"mime_type"`
+	Size     int    `json:"size"`
+	SHA1Hash string `json:"storage_sha1hash"`
+	Updated  int    `json:"updated"`
+}
+
+// AttachmentFile is a file to upload to Cerb, e.g. alongside a CustomerQuestion.
+type AttachmentFile struct {
+	Name     string
+	MimeType string
+	Data     []byte
+}
+
+// CreateAttachment uploads a file to Cerb. The optional attachTo values are record `context:id` tuples (e.g. "message:123") that the file is attached to.
+func (c Cerberus) CreateAttachment(name string, mimeType string, data []byte, attachTo ...string) (*Attachment, error) {
+	form := url.Values{}
+	form.Set("fields[name]", name)
+	form.Set("fields[mime_type]", mimeType)
+	form.Set("fields[content]", "data:"+mimeType+";base64,"+base64.StdEncoding.EncodeToString(data))
+
+	for _, target := range attachTo {
+		form.Add("fields[attach][]", target)
+	}
+
+	var a Attachment
+	err := c.performRequest(http.MethodPost, "records/attachment/create.json", nil, form, &a)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create attachment %s: %v", name, err)
+	}
+
+	return &a, nil
+}
@@ -0,0 +1,217 @@
+package cerb
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Bulk operations run a per-ticket function over many tickets with a bounded pool of goroutines. Requests still go through the client so they respect its rate limit (see WithRateLimit). Progress is tracked in a BulkCheckpoint which can be saved and passed back in to resume an interrupted run without repeating the tickets that already succeeded.
+
+// defaultBulkConcurrency is the number of tickets processed at once when BulkOptions.Concurrency isn't set
+const defaultBulkConcurrency = 4
+
+// TicketUpdate holds the changes to make to a ticket. Zero values are left unchanged.
+type TicketUpdate struct {
+	Status       string // [o]pen, [w]aiting, [c]losed, [d]eleted
+	GroupID      int
+	BucketID     int
+	OwnerID      int
+	Importance   int
+	Subject      string
+	CustomFields []CustomField
+}
+
+// BulkCheckpoint records which tickets a bulk operation has completed. It's JSON serializable so it can be persisted between runs.
+type BulkCheckpoint struct {
+	Completed []int `json:"completed"` // Sorted in BulkResult; in the order the tickets completed in progress updates
+}
+
+// BulkOptions controls how a bulk operation runs
+type BulkOptions struct {
+	// Concurrency is the maximum number of tickets processed at once. Defaults to 4.
+	Concurrency int
+	// Checkpoint from a previous run. Tickets it lists as completed are skipped.
+	Checkpoint *BulkCheckpoint
+	// OnProgress, when set, is called with an updated checkpoint after every ticket. Calls are serialized and the checkpoint's Completed mustn't be modified.
+	OnProgress func(checkpoint BulkCheckpoint)
+}
+
+// BulkResult reports the outcome of a bulk operation
+type BulkResult struct {
+	Succeeded  []int          // Tickets processed by this run
+	Skipped    []int          // Tickets already completed according to the checkpoint
+	Failed     TicketErrors   // Tickets that failed, keyed by ID
+	Checkpoint BulkCheckpoint // Pass this back in BulkOptions to retry only the failures
+}
+
+// UpdateTicket applies the update to a single ticket
+func (c Cerberus) UpdateTicket(ticketID int, update TicketUpdate) error {
+	form := url.Values{}
+
+	if update.Status != "" {
+		form.Set("fields[status]", update.Status)
+	}
+	if update.GroupID != 0 {
+		form.Set("fields[group_id]", strconv.Itoa(update.GroupID))
+	}
+	if update.BucketID != 0 {
+		form.Set("fields[bucket_id]", strconv.Itoa(update.BucketID))
+	}
+	if update.OwnerID != 0 {
+		form.Set("fields[owner_id]", strconv.Itoa(update.OwnerID))
+	}
+	if update.Importance != 0 {
+		form.Set("fields[importance]", strconv.Itoa(update.Importance))
+	}
+	if update.Subject != "" {
+		form.Set("fields[subject]", update.Subject)
+	}
+	for _, cf := range update.CustomFields {
+		form.Set("fields[custom_"+strconv.Itoa(cf.ID)+"]", cf.Value)
+	}
+
+	var r recordStatusResponse
+	err := c.performRequest(http.MethodPut, "records/ticket/"+strconv.Itoa(ticketID)+".json", nil, form, &r)
+
+	if err != nil {
+		return fmt.Errorf("Failed to update ticket %d: %v", ticketID, err)
+	}
+
+	return nil
+}
+
+// FindTicketIDs returns the IDs of every ticket matching the Cerb search query in ID order. Rather than paging by number the tickets are read in ID order from after the last ID seen, so tickets changing while the IDs are collected can't shift the pages and be skipped or repeated. opts.Sort and opts.Page are ignored.
+func (c Cerberus) FindTicketIDs(query string, opts ...SearchOptions) ([]int, error) {
+	limit := 250 // Maximum of 250 enforced by server
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(limit))
+	params.Set("fields", "id")
+	applySearchOptions(params, opts)
+	query = sortTermPattern.ReplaceAllString(params.Get("q"), "")
+	params.Set("page", "0")
+
+	var ids []int
+	last := 0
+	for {
+		params.Set("q", strings.TrimSpace(query+" id:>"+strconv.Itoa(last)+" sort:id"))
+
+		var r CerberusTicketSearchResults
+		err := c.performRequest(http.MethodGet, "records/ticket/search.json", params, nil, &r)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to search tickets after ID %d: %v", last, err)
+		}
+
+		for _, t := range r.Results {
+			if t.ID > last {
+				ids = append(ids, t.ID)
+				last = t.ID
+			}
+		}
+
+		if len(r.Results) == 0 || len(r.Results) >= r.Total {
+			break
+		}
+	}
+
+	return ids, nil
+}
+
+// BulkUpdateTickets applies the update to every ticket
+func (c Cerberus) BulkUpdateTickets(ticketIDs []int, update TicketUpdate, opts BulkOptions) *BulkResult {
+	return c.BulkTickets(ticketIDs, opts, func(c Cerberus, ticketID int) error {
+		return c.UpdateTicket(ticketID, update)
+	})
+}
+
+// BulkUpdateTicketsMatching applies the update to every ticket matching the Cerb search query. The matching IDs are collected before any ticket is updated so updates that change whether a ticket matches don't affect which tickets are processed.
+func (c Cerberus) BulkUpdateTicketsMatching(query string, update TicketUpdate, opts BulkOptions) (*BulkResult, error) {
+	ids, err := c.FindTicketIDs(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.BulkUpdateTickets(ids, update, opts), nil
+}
+
+// BulkTickets calls fn for every ticket using a pool of opts.Concurrency goroutines. Repeated IDs are only processed once.
+func (c Cerberus) BulkTickets(ticketIDs []int, opts BulkOptions, fn func(c Cerberus, ticketID int) error) *BulkResult {
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = defaultBulkConcurrency
+	}
+
+	done := map[int]bool{}
+	var completed []int
+	if opts.Checkpoint != nil {
+		for _, id := range opts.Checkpoint.Completed {
+			if !done[id] {
+				done[id] = true
+				completed = append(completed, id)
+			}
+		}
+	}
+
+	result := &BulkResult{Failed: TicketErrors{}}
+	queued := map[int]bool{}
+	var pending []int
+	for _, id := range ticketIDs {
+		if queued[id] {
+			continue
+		}
+		queued[id] = true
+
+		if done[id] {
+			result.Skipped = append(result.Skipped, id)
+		} else {
+			pending = append(pending, id)
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan int)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for id := range queue {
+				err := fn(c, id)
+
+				mu.Lock()
+				if err != nil {
+					result.Failed[id] = err
+				} else {
+					completed = append(completed, id)
+					result.Succeeded = append(result.Succeeded, id)
+				}
+				if opts.OnProgress != nil {
+					// Capping the capacity stops later appends or the callback from writing over the slice the other sees, so it needn't be copied
+					opts.OnProgress(BulkCheckpoint{Completed: completed[:len(completed):len(completed)]})
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, id := range pending {
+		queue <- id
+	}
+	close(queue)
+	wg.Wait()
+
+	sort.Ints(result.Succeeded)
+	result.Checkpoint = BulkCheckpoint{Completed: append([]int{}, completed...)}
+	sort.Ints(result.Checkpoint.Completed)
+
+	return result
+}
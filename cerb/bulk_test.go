@@ -0,0 +1,68 @@
+package cerb
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestBulkTickets(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[int]int{}
+	var progress []BulkCheckpoint
+
+	opts := BulkOptions{
+		Concurrency: 3,
+		Checkpoint:  &BulkCheckpoint{Completed: []int{2}},
+		OnProgress: func(cp BulkCheckpoint) {
+			progress = append(progress, cp)
+		},
+	}
+
+	result := Cerberus{}.BulkTickets([]int{5, 1, 2, 3, 5, 4, 1}, opts, func(c Cerberus, id int) error {
+		mu.Lock()
+		calls[id]++
+		mu.Unlock()
+		if id == 4 {
+			return errors.New("failed")
+		}
+		return nil
+	})
+
+	for id, n := range calls {
+		if n != 1 {
+			t.Errorf("ticket %d processed %d times, want 1", id, n)
+		}
+	}
+	if calls[2] != 0 {
+		t.Error("ticket 2 was completed by the checkpoint but processed again")
+	}
+
+	if want := []int{1, 3, 5}; !reflect.DeepEqual(result.Succeeded, want) {
+		t.Errorf("Succeeded = %v, want %v", result.Succeeded, want)
+	}
+	if want := []int{2}; !reflect.DeepEqual(result.Skipped, want) {
+		t.Errorf("Skipped = %v, want %v", result.Skipped, want)
+	}
+	if _, ok := result.Failed[4]; !ok || len(result.Failed) != 1 {
+		t.Errorf("Failed = %v, want ticket 4", result.Failed)
+	}
+	if want := []int{1, 2, 3, 5}; !reflect.DeepEqual(result.Checkpoint.Completed, want) {
+		t.Errorf("Checkpoint.Completed = %v, want %v", result.Checkpoint.Completed, want)
+	}
+
+	if len(progress) != 4 {
+		t.Fatalf("OnProgress called %d times, want 4", len(progress))
+	}
+	// Each update extends the previous one without changing what an earlier update was given
+	for i := 1; i < len(progress); i++ {
+		prev, cp := progress[i-1].Completed, progress[i].Completed
+		if len(cp) < len(prev) || !reflect.DeepEqual(cp[:len(prev)], prev) {
+			t.Errorf("progress %d = %v doesn't extend %v", i, cp, prev)
+		}
+	}
+	if last := progress[len(progress)-1]; len(last.Completed) != 4 {
+		t.Errorf("last progress has %d completed tickets, want 4", len(last.Completed))
+	}
+}
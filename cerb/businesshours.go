@@ -0,0 +1,87 @@
+package cerb
+
+import (
+	"time"
+)
+
+// Shift is a period of working hours within a day, as offsets from midnight, e.g. {9 * time.Hour, 17*time.Hour + 30*time.Minute}. A shift ending at or before its start, e.g. {22 * time.Hour, 6 * time.Hour}, runs past midnight into the next day.
+type Shift struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// BusinessHours is a weekly calendar of working hours used to measure response times in working time. A nil *BusinessHours counts every hour of every day.
+type BusinessHours struct {
+	Location *time.Location           // Time zone the shifts are in. Defaults to UTC.
+	Days     map[time.Weekday][]Shift // Days without shifts are closed
+	Holidays []time.Time              // Dates the office is closed. Only the year, month and day are used.
+}
+
+// NewBusinessHours creates a calendar with the same shift on each of the days, e.g. 9 to 5 on weekdays:
+//
+//	NewBusinessHours(loc, 9*time.Hour, 17*time.Hour, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+func NewBusinessHours(loc *time.Location, start time.Duration, end time.Duration, days ...time.Weekday) *BusinessHours {
+	b := &BusinessHours{Location: loc, Days: map[time.Weekday][]Shift{}}
+	for _, d := range days {
+		b.Days[d] = []Shift{{Start: start, End: end}}
+	}
+	return b
+}
+
+// Elapsed returns the working time between from and to
+func (b *BusinessHours) Elapsed(from time.Time, to time.Time) time.Duration {
+	if !to.After(from) {
+		return 0
+	}
+	if b == nil {
+		return to.Sub(from)
+	}
+
+	loc := b.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var elapsed time.Duration
+	y, m, d := from.In(loc).Date()
+	// Start the day before so a shift running past midnight into from's day is counted
+	for day := time.Date(y, m, d-1, 0, 0, 0, 0, loc); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if b.isHoliday(day) {
+			continue
+		}
+
+		for _, s := range b.Days[day.Weekday()] {
+			endOffset := s.End
+			if endOffset <= s.Start {
+				endOffset += 24 * time.Hour
+			}
+
+			// Building the shift from the wall clock keeps it right on days with a daylight saving change
+			start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, int(s.Start.Seconds()), 0, loc)
+			end := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, int(endOffset.Seconds()), 0, loc)
+
+			if start.Before(from) {
+				start = from
+			}
+			if end.After(to) {
+				end = to
+			}
+			if end.After(start) {
+				elapsed += end.Sub(start)
+			}
+		}
+	}
+
+	return elapsed
+}
+
+func (b *BusinessHours) isHoliday(day time.Time) bool {
+	y, m, d := day.Date()
+	for _, h := range b.Holidays {
+		hy, hm, hd := h.Date()
+		if hy == y && hm == m && hd == d {
+			return true
+		}
+	}
+	return false
+}
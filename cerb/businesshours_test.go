@@ -0,0 +1,64 @@
+package cerb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBusinessHoursElapsed(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	nineToFive := NewBusinessHours(time.UTC, 9*time.Hour, 17*time.Hour, weekdays...)
+
+	withHoliday := NewBusinessHours(time.UTC, 9*time.Hour, 17*time.Hour, weekdays...)
+	withHoliday.Holidays = []time.Time{time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC)}
+
+	nights := NewBusinessHours(time.UTC, 22*time.Hour, 6*time.Hour, weekdays...)
+	allDayNewYork := NewBusinessHours(newYork, 0, 24*time.Hour, time.Sunday)
+	nineToFiveNewYork := NewBusinessHours(newYork, 9*time.Hour, 17*time.Hour, time.Sunday, time.Monday)
+
+	utc := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2026, month, day, hour, min, 0, 0, time.UTC)
+	}
+	ny := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 0, 0, 0, newYork)
+	}
+
+	tests := []struct {
+		name  string
+		hours *BusinessHours
+		from  time.Time
+		to    time.Time
+		want  time.Duration
+	}{
+		{"nil counts wall clock time", nil, utc(10, 16, 16, 0), utc(10, 19, 10, 0), 66 * time.Hour},
+		{"to before from", nineToFive, utc(10, 19, 10, 0), utc(10, 19, 9, 0), 0},
+		{"within a shift", nineToFive, utc(10, 19, 10, 0), utc(10, 19, 12, 30), 150 * time.Minute},
+		{"before and after hours", nineToFive, utc(10, 19, 6, 0), utc(10, 19, 20, 0), 8 * time.Hour},
+		{"over a weekend", nineToFive, utc(10, 16, 16, 0), utc(10, 19, 10, 0), 2 * time.Hour},
+		{"entirely on a weekend", nineToFive, utc(10, 17, 9, 0), utc(10, 18, 17, 0), 0},
+		{"a full week", nineToFive, utc(10, 19, 0, 0), utc(10, 26, 0, 0), 40 * time.Hour},
+		{"skips holidays", withHoliday, utc(12, 24, 9, 0), utc(12, 26, 0, 0), 8 * time.Hour},
+		{"holidays ignore the time of day", withHoliday, utc(12, 25, 12, 0), utc(12, 25, 13, 0), 0},
+		{"shift past midnight", nights, utc(10, 19, 21, 0), utc(10, 20, 7, 0), 8 * time.Hour},
+		{"starting after midnight in a shift", nights, utc(10, 20, 1, 0), utc(10, 20, 3, 0), 2 * time.Hour},
+		{"shift past midnight into the weekend", nights, utc(10, 24, 0, 0), utc(10, 25, 0, 0), 6 * time.Hour},
+		{"spring forward day is 23 hours", allDayNewYork, ny(3, 8, 0), ny(3, 9, 0), 23 * time.Hour},
+		{"fall back day is 25 hours", allDayNewYork, ny(11, 1, 0), ny(11, 2, 0), 25 * time.Hour},
+		{"daylight saving keeps shifts on the wall clock", nineToFiveNewYork, ny(3, 8, 0), ny(3, 10, 0), 16 * time.Hour},
+		{"shifts are in the calendar's time zone", nineToFiveNewYork, utc(3, 9, 12, 0), utc(3, 9, 14, 0), time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.hours.Elapsed(tt.from, tt.to)
+			if got != tt.want {
+				t.Errorf("Elapsed(%v, %v) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
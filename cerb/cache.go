@@ -0,0 +1,234 @@
+package cerb
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Groups, buckets, workers and custom field definitions rarely change so the client caches them. The cache is pluggable: NewCerberus uses an in-memory LRU but any backend implementing Cache (e.g. one shared between processes) can be passed to WithCache.
+
+// Keys used for the reference data cached by the client. Pass them to InvalidateCache to reload a single kind of data.
+const (
+	CacheKeyGroups       = "cerb:groups_and_buckets"
+	CacheKeyWorkers      = "cerb:workers"
+	CacheKeyCustomFields = "cerb:custom_fields"
+)
+
+// DefaultCacheTTL is how long reference data is cached by a client created with NewCerberus
+const DefaultCacheTTL = 10 * time.Minute
+
+// defaultCacheSize is the number of entries kept by the default LRU cache. There are only a handful of reference data keys so this is generous.
+const defaultCacheSize = 128
+
+// Cache stores reference data for the client. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the value for key, or false when it's missing or expired
+	Get(key string) (interface{}, bool)
+	// Set stores the value for key, expiring it after ttl
+	Set(key string, value interface{}, ttl time.Duration)
+	// Delete removes key from the cache
+	Delete(key string)
+	// Purge removes everything from the cache
+	Purge()
+}
+
+// referenceCache pairs the cache backend with the TTL used for reference data. It's shared by every copy of a Cerberus.
+type referenceCache struct {
+	backend Cache
+	ttl     time.Duration
+	loading sync.Mutex // Serializes loads so concurrent callers don't all fetch the same data
+}
+
+// WithCache returns a copy of the client that caches reference data in the given cache for ttl. Pass a nil cache to disable caching.
+func (c Cerberus) WithCache(cache Cache, ttl time.Duration) Cerberus {
+	if cache == nil {
+		c.cache = nil
+		return c
+	}
+
+	c.cache = &referenceCache{backend: cache, ttl: ttl}
+	return c
+}
+
+// cached returns the value for key, calling load to fetch (and cache) it when it's missing
+func (c Cerberus) cached(key string, load func() (interface{}, error)) (interface{}, error) {
+	if c.cache == nil {
+		return load()
+	}
+
+	if v, ok := c.cache.backend.Get(key); ok {
+		return v, nil
+	}
+
+	c.cache.loading.Lock()
+	defer c.cache.loading.Unlock()
+
+	// Another caller may have loaded it while we were waiting
+	if v, ok := c.cache.backend.Get(key); ok {
+		return v, nil
+	}
+
+	v, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	c.cache.backend.Set(key, v, c.cache.ttl)
+	return v, nil
+}
+
+// InvalidateCache removes the given keys (e.g. CacheKeyWorkers) from the client's cache so they're reloaded on next use. With no keys the entire cache is purged.
+func (c Cerberus) InvalidateCache(keys ...string) {
+	if c.cache == nil {
+		return
+	}
+
+	if len(keys) == 0 {
+		c.cache.backend.Purge()
+		return
+	}
+
+	for _, key := range keys {
+		c.cache.backend.Delete(key)
+	}
+}
+
+// PrewarmCache loads all cached reference data so the first lookups after startup don't pay for it
+func (c Cerberus) PrewarmCache() error {
+	_, err := c.CachedGroupsAndBuckets()
+	if err != nil {
+		return err
+	}
+
+	_, err = c.CachedWorkers()
+	if err != nil {
+		return err
+	}
+
+	_, err = c.CachedCustomFields()
+	return err
+}
+
+// CachedGroupsAndBuckets is FindAllGroupsAndBuckets served from the client's cache. The returned slice is shared so don't modify it.
+func (c Cerberus) CachedGroupsAndBuckets() ([]Group, error) {
+	v, err := c.cached(CacheKeyGroups, func() (interface{}, error) {
+		groups, err := c.FindAllGroupsAndBuckets()
+		if err != nil {
+			return nil, err
+		}
+		return *groups, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return v.([]Group), nil
+}
+
+// CachedCustomFields is FindAllCustomFields served from the client's cache. The returned slice is shared so don't modify it.
+func (c Cerberus) CachedCustomFields() ([]CustomFieldDefinition, error) {
+	v, err := c.cached(CacheKeyCustomFields, func() (interface{}, error) {
+		fields, err := c.FindAllCustomFields()
+		if err != nil {
+			return nil, err
+		}
+		return *fields, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return v.([]CustomFieldDefinition), nil
+}
+
+// LRUCache is an in-memory Cache that evicts the least recently used entry once it holds maxEntries
+type LRUCache struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // Front is most recently used
+}
+
+type lruEntry struct {
+	key     string
+	value   interface{}
+	expires time.Time
+}
+
+// NewLRUCache creates an LRUCache holding at most maxEntries values
+func NewLRUCache(maxEntries int) *LRUCache {
+	return &LRUCache{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		order:      list.New(),
+	}
+}
+
+// Get returns the value for key, or false when it's missing or expired
+func (l *LRUCache) Get(key string) (interface{}, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	el, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	e := el.Value.(*lruEntry)
+	if time.Now().After(e.expires) {
+		l.order.Remove(el)
+		delete(l.entries, key)
+		return nil, false
+	}
+
+	l.order.MoveToFront(el)
+	return e.value, true
+}
+
+// Set stores the value for key, expiring it after ttl
+func (l *LRUCache) Set(key string, value interface{}, ttl time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	expires := time.Now().Add(ttl)
+
+	if el, ok := l.entries[key]; ok {
+		e := el.Value.(*lruEntry)
+		e.value = value
+		e.expires = expires
+		l.order.MoveToFront(el)
+		return
+	}
+
+	l.entries[key] = l.order.PushFront(&lruEntry{key: key, value: value, expires: expires})
+
+	for l.maxEntries > 0 && l.order.Len() > l.maxEntries {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Delete removes key from the cache
+func (l *LRUCache) Delete(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if el, ok := l.entries[key]; ok {
+		l.order.Remove(el)
+		delete(l.entries, key)
+	}
+}
+
+// Purge removes everything from the cache
+func (l *LRUCache) Purge() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = map[string]*list.Element{}
+	l.order.Init()
+}
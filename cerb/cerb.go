@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 // CerberusCreds contains the keys needed to connect to the Cerberus API. @see https://cerb.ai/docs/api/authentication/
@@ -17,8 +18,10 @@ type CerberusCreds struct {
 
 // Cerberus handles all the interaction with the Cerb API.
 type Cerberus struct {
-	creds  CerberusCreds
-	client http.Client
+	creds   CerberusCreds
+	client  http.Client
+	cache   *referenceCache
+	limiter *rateLimiter
 }
 
 // NewCerberus create a new Cerberus
@@ -26,6 +29,7 @@ func NewCerberus(creds CerberusCreds, client http.Client) Cerberus {
 	c := Cerberus{
 		creds:  creds,
 		client: client,
+		cache:  &referenceCache{backend: NewLRUCache(defaultCacheSize), ttl: DefaultCacheTTL},
 	}
 	return c
 }
@@ -42,7 +46,10 @@ type CerberusTicket struct {
 	Status      string `json:"status"`
 	URL         string `json:"url"`
 	Created     int    `json:"created"`
+	Updated     int    `json:"updated"`
 	ClosedAt    int    `json:"closed_at"`
+
+	ElapsedResponseFirst int `json:"elapsed_response_first"` // Seconds until the first worker reply, 0 when nobody has replied yet
 }
 
 // CerberusTicketSearchResults is the raw structure returned by the Cerberus search API when looking for tickets. Most often you want to call a function that hides all these details and work with a []CerberusTicket instead.
@@ -82,18 +89,22 @@ type CreateCommentResponse struct {
 
 // CustomerQuestion represents a question asked by a user that needs to be created as a Ticket in Cerb. Additional fields allow you to control where to create the ticket, notes to add, initial status, etc.
 type CustomerQuestion struct {
-	BucketID int
-	GroupID  int
+	BucketID    int
+	GroupID     int
+	Destination string // "Group/Bucket" or "Group" resolved by name. Overrides BucketID and GroupID when set.
 
 	To           string
 	From         string
 	Participants []string
 	Subject      string
-	Content      string
+	Content      string // Plain-text body. Generated from HTMLContent when empty.
+	HTMLContent  string // Optional HTML body. Sanitized before being sent to Cerb.
 
+	Attachments  []AttachmentFile
 	CustomFields []CustomField
 	Notes        string
 	Status       string // [o]pen, [c]losed, [w]aiting. Defaults to [o]
+	Watchers     []int  // IDs of workers to notify about changes to the ticket
 }
 
 // CustomField allows records in Cerb can be extended with custom fields. @see https://cerb.ai/docs/api/topics/custom-fields/
@@ -140,6 +151,15 @@ func (c Cerberus) CreateMessage(q CustomerQuestion) (*CreateMessageResponse, err
 	if status == "" {
 		status = "o"
 	}
+	if q.Destination != "" {
+		route, err := c.ResolveDestination(q.Destination)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to route Cerberus ticket: %w", err)
+		}
+		q.GroupID = route.GroupID
+		q.BucketID = route.BucketID
+	}
+
 	participants := strings.Join(q.Participants, ", ")
 	form := url.Values{}
 	form.Set("fields[group_id]", strconv.Itoa(q.GroupID))
@@ -155,6 +175,15 @@ func (c Cerberus) CreateMessage(q CustomerQuestion) (*CreateMessageResponse, err
 		return nil, fmt.Errorf("Failed to create Cerberus ticket: %v", err)
 	}
 
+	content := q.Content
+	sanitized := ""
+	if q.HTMLContent != "" {
+		sanitized = SanitizeHTML(q.HTMLContent)
+		if content == "" {
+			content = HTMLToText(sanitized)
+		}
+	}
+
 	// Create a message on the newly created ticket
 	headers := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s", q.From, q.To, q.Subject)
 	form = url.Values{}
@@ -162,7 +191,7 @@ func (c Cerberus) CreateMessage(q CustomerQuestion) (*CreateMessageResponse, err
 	form.Set("fields[ticket_id]", strconv.Itoa(ticket.ID))
 	form.Set("fields[sender]", q.From)
 	form.Set("fields[headers]", headers)
-	form.Set("fields[content]", q.Content)
+	form.Set("fields[content]", content)
 
 	var message CreateMessageResponse
 	err = c.performRequest(http.MethodPost, "records/message/create.json", nil, form, &message)
@@ -171,8 +200,30 @@ func (c Cerberus) CreateMessage(q CustomerQuestion) (*CreateMessageResponse, err
 		return nil, fmt.Errorf("Failed to create Cerberus message on ticket %d: %v", ticket.ID, err)
 	}
 
+	// Upload the sanitized HTML part once the message exists so the file is attached to it and Cerb can display the formatted version
+	if sanitized != "" {
+		err = c.setMessageHTML(message.ID, sanitized)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, a := range q.Attachments {
+		_, err = c.CreateAttachment(a.Name, a.MimeType, a.Data, "message:"+strconv.Itoa(message.ID))
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to attach %s to message %d: %v", a.Name, message.ID, err)
+		}
+	}
+
 	c.SetCustomTicketFields(ticket.ID, q.CustomFields)
 
+	err = c.AddWatchers(RecordRef{Context: "ticket", ID: ticket.ID}, q.Watchers...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to add watchers to ticket %d: %v", ticket.ID, err)
+	}
+
 	if q.Notes != "" {
 		err = c.CreateNote(message.ID, q.Notes)
 
@@ -184,6 +235,26 @@ func (c Cerberus) CreateMessage(q CustomerQuestion) (*CreateMessageResponse, err
 	return &message, nil
 }
 
+// setMessageHTML uploads html as the message's HTML part
+func (c Cerberus) setMessageHTML(messageID int, html string) error {
+	a, err := c.CreateAttachment("original_message.html", "text/html", []byte(html), "message:"+strconv.Itoa(messageID))
+	if err != nil {
+		return fmt.Errorf("Failed to upload HTML content for message %d: %v", messageID, err)
+	}
+
+	form := url.Values{}
+	form.Set("fields[html_attachment_id]", strconv.Itoa(a.ID))
+
+	var r recordStatusResponse
+	err = c.performRequest(http.MethodPut, "records/message/"+strconv.Itoa(messageID)+".json", nil, form, &r)
+
+	if err != nil {
+		return fmt.Errorf("Failed to set HTML content of message %d: %v", messageID, err)
+	}
+
+	return nil
+}
+
 // CreateComment adds a comment to an existing ticket
 func (c Cerberus) CreateComment(ticketID int, comment string) error {
 	params := url.Values{}
@@ -240,14 +311,30 @@ func (c Cerberus) SetCustomTicketFields(ticketID int, customFields []CustomField
 	return nil
 }
 
+// GetTicket loads the ticket with the given ID
+func (c Cerberus) GetTicket(ticketID int) (*CerberusTicket, error) {
+	params := url.Values{}
+	params.Set("expand", "initial_message_sender_")
+
+	var t CerberusTicket
+	err := c.performRequest(http.MethodGet, "records/ticket/"+strconv.Itoa(ticketID)+".json", params, nil, &t)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get ticket %d: %v", ticketID, err)
+	}
+
+	return &t, nil
+}
+
 // FindTicketsByEmail finds all tickets for the given email address.
 // Cerb's API is paginated but we do not implement that, so this only returns
 // the first 250 results.
-func (c Cerberus) FindTicketsByEmail(email string) (*[]CerberusTicket, error) {
+func (c Cerberus) FindTicketsByEmail(email string, opts ...SearchOptions) (*[]CerberusTicket, error) {
 	limit := 250 // Maximum of 250 enforced by server
 	params := url.Values{}
 	params.Set("q", "messages.first:(sender:(email:"+email+"))")
 	params.Set("limit", strconv.Itoa(limit))
+	applySearchOptions(params, opts)
 
 	var r CerberusTicketSearchResults
 	err := c.performRequest(http.MethodGet, "records/ticket/search.json", params, nil, &r)
@@ -260,13 +347,15 @@ func (c Cerberus) FindTicketsByEmail(email string) (*[]CerberusTicket, error) {
 }
 
 // ListOpenTickets finds all open tickets in Cerberus. The Cerb api returns things grouped by pages so the caller needs to specify which page they want. Returns the first page of matching tickets and the number of additional tickets remaining on subsequent pages.
-func (c Cerberus) ListOpenTickets(page int) (*[]CerberusTicket, int, error) {
+func (c Cerberus) ListOpenTickets(page int, opts ...SearchOptions) (*[]CerberusTicket, int, error) {
 	limit := 100 // Maximum of 250 enforced by server
 	params := url.Values{}
 	params.Set("q", "status:[o]")
-	params.Set("page", strconv.Itoa(page))
 	params.Set("limit", strconv.Itoa(limit))
 	params.Set("expand", "initial_message_sender_")
+	applySearchOptions(params, opts)
+	params.Set("page", strconv.Itoa(page)) // The page argument wins over SearchOptions.Page
+	limit = searchLimit(params)
 
 	var r CerberusTicketSearchResults
 	err := c.performRequest(http.MethodGet, "records/ticket/search.json", params, nil, &r)
@@ -336,90 +425,149 @@ type Bucket struct {
 	GroupName string `json:"group_name"`
 }
 
-// FindAllGroups searches for all groups
-func (c Cerberus) FindAllGroups() (*[]Group, error) {
-	limit := 250 // If you need pagination imitate ListOpenTickets
+// FindAllGroups searches for all groups, following Cerb's pagination so instances with more than 250 groups get all of them
+func (c Cerberus) FindAllGroups(opts ...SearchOptions) (*[]Group, error) {
+	limit := 250 // Maximum of 250 enforced by server
 	params := url.Values{}
 	params.Set("q", "")
 	params.Set("limit", strconv.Itoa(limit))
+	applySearchOptions(params, opts)
 
-	var r SearchGroupResponse
-	err := c.performRequest(http.MethodGet, "records/group/search.json", params, nil, &r)
+	groups := []Group{}
+	for page := 0; ; page++ {
+		params.Set("page", strconv.Itoa(page))
 
-	if err != nil {
-		return nil, fmt.Errorf("ListGroups failed to search groups: %v", err)
-	}
+		var r SearchGroupResponse
+		err := c.performRequest(http.MethodGet, "records/group/search.json", params, nil, &r)
 
-	if err != nil {
-		return nil, fmt.Errorf("Failed to unmarshall ListGroups search results: %v", err)
+		if err != nil {
+			return nil, fmt.Errorf("ListGroups failed to search groups on page %d: %v", page, err)
+		}
+
+		groups = append(groups, r.Results...)
+		if len(r.Results) == 0 || len(groups) >= r.Total {
+			break
+		}
 	}
 
-	return &r.Results, nil
+	return &groups, nil
+}
+
+// FindAllBuckets will search Cerb for all buckets across every group, following Cerb's pagination
+func (c Cerberus) FindAllBuckets(opts ...SearchOptions) (*[]Bucket, error) {
+	return c.searchAllBuckets("", opts)
+}
+
+// FindBucketsInGroup will search Cerb for buckets within the given group
+func (c Cerberus) FindBucketsInGroup(groupID int, opts ...SearchOptions) (*[]Bucket, error) {
+	return c.searchAllBuckets("group.id:["+strconv.Itoa(groupID)+"]", opts)
 }
 
-// FindAllBuckets will search Cerb for buckets
-func (c Cerberus) FindAllBuckets() (*[]Bucket, error) {
-	limit := 250 // If you need pagination imitate ListOpenTickets
+func (c Cerberus) searchAllBuckets(query string, opts []SearchOptions) (*[]Bucket, error) {
+	limit := 250 // Maximum of 250 enforced by server
 	params := url.Values{}
+	params.Set("q", query)
 	params.Set("limit", strconv.Itoa(limit))
 	params.Set("expand", "group_")
+	applySearchOptions(params, opts)
 
-	var r SearchBucketsResponse
-	err := c.performRequest(http.MethodGet, "records/bucket/search.json", params, nil, &r)
+	buckets := []Bucket{}
+	for page := 0; ; page++ {
+		params.Set("page", strconv.Itoa(page))
 
-	if err != nil {
-		return nil, fmt.Errorf("ListGroups failed to search buckets: %v", err)
-	}
+		var r SearchBucketsResponse
+		err := c.performRequest(http.MethodGet, "records/bucket/search.json", params, nil, &r)
 
-	if err != nil {
-		return nil, fmt.Errorf("Failed to unmarshall FindBuckets search results: %v", err)
+		if err != nil {
+			return nil, fmt.Errorf("ListGroups failed to search buckets on page %d: %v", page, err)
+		}
+
+		buckets = append(buckets, r.Results...)
+		if len(r.Results) == 0 || len(buckets) >= r.Total {
+			break
+		}
 	}
 
-	return &r.Results, nil
+	return &buckets, nil
 }
 
-// FindBucketsInGroup will search Cerb for buckets within the given group
-func (c Cerberus) FindBucketsInGroup(groupID int) (*[]Bucket, error) {
-	limit := 250 // If you need pagination imitate ListOpenTickets
-	params := url.Values{}
-	params.Set("q", "group.id:["+strconv.Itoa(groupID)+"]")
-	params.Set("limit", strconv.Itoa(limit))
-	params.Set("expand", "group_")
-
-	var r SearchBucketsResponse
-	err := c.performRequest(http.MethodGet, "records/bucket/search.json", params, nil, &r)
+// FindAllGroupsAndBuckets searches Cerb for all Groups and the Buckets within them. Buckets are fetched with a single (paginated) search and joined to their groups locally so this takes two requests no matter how many groups there are.
+func (c Cerberus) FindAllGroupsAndBuckets() (*[]Group, error) {
+	groups, err := c.FindAllGroups()
 
 	if err != nil {
-		return nil, fmt.Errorf("ListGroups failed to search buckets: %v", err)
+		return nil, fmt.Errorf("error listing groups: %v", err)
 	}
 
+	buckets, err := c.FindAllBuckets()
+
 	if err != nil {
-		return nil, fmt.Errorf("Failed to unmarshall FindBuckets search results: %v", err)
+		return nil, fmt.Errorf("AllBucketsByGroup failed to find buckets: %v", err)
 	}
 
-	return &r.Results, nil
+	byGroup := map[int][]Bucket{}
+	for _, b := range *buckets {
+		byGroup[b.GroupID] = append(byGroup[b.GroupID], b)
+	}
+
+	for i, group := range *groups {
+		groupBuckets := byGroup[group.ID]
+		for j := range groupBuckets {
+			groupBuckets[j].GroupName = group.Name
+		}
+
+		(*groups)[i].Buckets = groupBuckets
+	}
+
+	return groups, nil
 }
 
-// FindAllGroupsAndBuckets searches Cerb for all Groups and the Buckets within them.
-func (c Cerberus) FindAllGroupsAndBuckets() (*[]Group, error) {
+// FindAllGroupsAndBucketsConcurrently is the fallback for FindAllGroupsAndBuckets when the buckets of each group need to be searched separately (e.g. when the API key is restricted to per-group bucket queries). At most maxConcurrent bucket searches run at once.
+func (c Cerberus) FindAllGroupsAndBucketsConcurrently(maxConcurrent int) (*[]Group, error) {
 	groups, err := c.FindAllGroups()
 
 	if err != nil {
 		return nil, fmt.Errorf("error listing groups: %v", err)
 	}
 
-	for i, group := range *groups {
-		buckets, err := c.FindBucketsInGroup(group.ID)
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
 
-		if err != nil {
-			return nil, fmt.Errorf("AllBucketsByGroup failed to find buckets in group %d: %v", group.ID, err)
-		}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrent)
+	errs := make([]error, len(*groups))
 
-		for j := range *buckets {
-			(*buckets)[j].GroupName = group.Name
-		}
+	for i := range *groups {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			group := &(*groups)[i]
+			buckets, err := c.FindBucketsInGroup(group.ID)
+
+			if err != nil {
+				errs[i] = fmt.Errorf("AllBucketsByGroup failed to find buckets in group %d: %v", group.ID, err)
+				return
+			}
 
-		(*groups)[i].Buckets = *buckets
+			for j := range *buckets {
+				(*buckets)[j].GroupName = group.Name
+			}
+
+			group.Buckets = *buckets
+		}(i)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return groups, nil
@@ -0,0 +1,102 @@
+package cerb
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TicketErrors collects the per-ticket failures of a bulk operation, keyed by ticket ID
+type TicketErrors map[int]error
+
+func (e TicketErrors) Error() string {
+	ids := make([]int, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	msgs := make([]string, len(ids))
+	for i, id := range ids {
+		msgs[i] = fmt.Sprintf("ticket %d: %v", id, e[id])
+	}
+
+	return fmt.Sprintf("%d tickets failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// SoftDeleteTicket sets the ticket's status to [d]eleted. Cerb hides it from worklists and purges it later, until then it can be restored by changing its status.
+func (c Cerberus) SoftDeleteTicket(ticketID int) error {
+	form := url.Values{}
+	form.Set("fields[status]", "d")
+
+	var r recordStatusResponse
+	err := c.performRequest(http.MethodPut, "records/ticket/"+strconv.Itoa(ticketID)+".json", nil, form, &r)
+
+	if err != nil {
+		return fmt.Errorf("Failed to soft delete ticket %d: %v", ticketID, err)
+	}
+
+	return nil
+}
+
+// DeleteTicket permanently deletes the ticket along with its messages. This can't be undone.
+func (c Cerberus) DeleteTicket(ticketID int) error {
+	return c.deleteRecord("ticket", ticketID)
+}
+
+// TrainTicket trains Cerb's Bayesian spam filter with the ticket, as [S]pam when spam is true and as [N]ot spam otherwise
+func (c Cerberus) TrainTicket(ticketID int, spam bool) error {
+	training := "N"
+	if spam {
+		training = "S"
+	}
+
+	form := url.Values{}
+	form.Set("fields[spam_training]", training)
+
+	var r recordStatusResponse
+	err := c.performRequest(http.MethodPut, "records/ticket/"+strconv.Itoa(ticketID)+".json", nil, form, &r)
+
+	if err != nil {
+		return fmt.Errorf("Failed to train ticket %d as %s: %v", ticketID, training, err)
+	}
+
+	return nil
+}
+
+// DeleteTickets deletes each of the tickets, permanently or by setting their status to [d]eleted. Every ticket is attempted; failures are returned as TicketErrors.
+func (c Cerberus) DeleteTickets(ticketIDs []int, permanent bool) error {
+	return eachTicket(ticketIDs, func(id int) error {
+		if permanent {
+			return c.DeleteTicket(id)
+		}
+		return c.SoftDeleteTicket(id)
+	})
+}
+
+// TrainTickets trains Cerb's spam filter with each of the tickets. Every ticket is attempted; failures are returned as TicketErrors.
+func (c Cerberus) TrainTickets(ticketIDs []int, spam bool) error {
+	return eachTicket(ticketIDs, func(id int) error {
+		return c.TrainTicket(id, spam)
+	})
+}
+
+func eachTicket(ticketIDs []int, fn func(id int) error) error {
+	errs := TicketErrors{}
+
+	for _, id := range ticketIDs {
+		err := fn(id)
+		if err != nil {
+			errs[id] = err
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package cerb
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Contact represents a person in Cerb. A contact can have many email addresses and belong to one organization. @see https://cerb.ai/docs/records/types/contact/
+type Contact struct {
+	ID        int    `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Title     string `json:"title"`
+	Username  string `json:"username"`
+	Gender    string `json:"gender"` // [M]ale, [F]emale or empty
+	Location  string `json:"location"`
+	Phone     string `json:"phone"`
+	Mobile    string `json:"mobile"`
+	Language  string `json:"language"`
+	Timezone  string `json:"timezone"`
+
+	EmailID int    `json:"email_id"`
+	Email   string `json:"email_address"` // Only set when `email_` is expanded
+	OrgID   int    `json:"org_id"`
+	OrgName string `json:"org_name"` // Only set when `org_` is expanded
+
+	URL     string `json:"record_url"`
+	Created int    `json:"created"`
+	Updated int    `json:"updated"`
+}
+
+// SearchContactsResponse is the response from the records/contact/search.json endpoint
+type SearchContactsResponse struct {
+	Status  string    `json:"__status"`
+	Count   int       `json:"count"`
+	Limit   int       `json:"limit"`
+	Page    int       `json:"page"`
+	Results []Contact `json:"results"`
+	Total   int       `json:"total"`
+	Version string    `json:"__version"`
+}
+
+// contactForm converts the non-zero fields of ct into the form used by the create and update endpoints
+func contactForm(ct Contact) url.Values {
+	form := url.Values{}
+	form.Set("expand", "email_,org_")
+	fields := map[string]string{
+		"first_name": ct.FirstName,
+		"last_name":  ct.LastName,
+		"title":      ct.Title,
+		"username":   ct.Username,
+		"gender":     ct.Gender,
+		"location":   ct.Location,
+		"phone":      ct.Phone,
+		"mobile":     ct.Mobile,
+		"language":   ct.Language,
+		"timezone":   ct.Timezone,
+	}
+
+	for k, v := range fields {
+		if v != "" {
+			form.Set("fields["+k+"]", v)
+		}
+	}
+
+	if ct.EmailID != 0 {
+		form.Set("fields[email_id]", strconv.Itoa(ct.EmailID))
+	}
+	if ct.OrgID != 0 {
+		form.Set("fields[org_id]", strconv.Itoa(ct.OrgID))
+	}
+
+	return form
+}
+
+// CreateContact creates a new contact. Use UpsertContactByEmail to avoid creating duplicates.
+func (c Cerberus) CreateContact(ct Contact) (*Contact, error) {
+	var created Contact
+	err := c.performRequest(http.MethodPost, "records/contact/create.json", nil, contactForm(ct), &created)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create contact: %v", err)
+	}
+
+	return &created, nil
+}
+
+// GetContact loads the contact with the given ID
+func (c Cerberus) GetContact(contactID int) (*Contact, error) {
+	params := url.Values{}
+	params.Set("expand", "email_,org_")
+
+	var ct Contact
+	err := c.performRequest(http.MethodGet, "records/contact/"+strconv.Itoa(contactID)+".json", params, nil, &ct)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get contact %d: %v", contactID, err)
+	}
+
+	return &ct, nil
+}
+
+// UpdateContact updates the contact with the non-zero fields of ct. ct.ID must be set.
+func (c Cerberus) UpdateContact(ct Contact) (*Contact, error) {
+	var updated Contact
+	err := c.performRequest(http.MethodPut, "records/contact/"+strconv.Itoa(ct.ID)+".json", nil, contactForm(ct), &updated)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update contact %d: %v", ct.ID, err)
+	}
+
+	return &updated, nil
+}
+
+// DeleteContact permanently deletes the contact with the given ID. Their email addresses are kept.
+func (c Cerberus) DeleteContact(contactID int) error {
+	return c.deleteRecord("contact", contactID)
+}
+
+// SearchContacts finds contacts matching the given Cerb search query, e.g. `org:(name:"AgileBits")`
+func (c Cerberus) SearchContacts(query string, opts ...SearchOptions) (*[]Contact, error) {
+	limit := 250 // If you need pagination imitate ListOpenTickets
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(limit))
+	params.Set("expand", "email_,org_")
+	applySearchOptions(params, opts)
+
+	var r SearchContactsResponse
+	err := c.performRequest(http.MethodGet, "records/contact/search.json", params, nil, &r)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to search contacts: %v", err)
+	}
+
+	return &r.Results, nil
+}
+
+// LinkContactToOrg makes the contact a member of the organization
+func (c Cerberus) LinkContactToOrg(contactID int, orgID int) error {
+	_, err := c.UpdateContact(Contact{ID: contactID, OrgID: orgID})
+	return err
+}
+
+// AddContactEmail associates the email address with the contact, creating the address record if Cerb hasn't seen it before. The first address added becomes the contact's primary email.
+func (c Cerberus) AddContactEmail(contactID int, email string) error {
+	addr, err := c.FindOrCreateAddress(email)
+	if err != nil {
+		return err
+	}
+
+	err = c.SetAddressContact(addr.ID, contactID)
+	if err != nil {
+		return err
+	}
+
+	ct, err := c.GetContact(contactID)
+	if err != nil {
+		return err
+	}
+
+	if ct.EmailID == 0 {
+		_, err = c.UpdateContact(Contact{ID: contactID, EmailID: addr.ID})
+	}
+
+	return err
+}
+
+// UpsertContactByEmail finds the contact that owns the given email address and updates it with the non-zero fields of ct, or creates a new contact with that primary email when there isn't one. The returned bool is true when a new contact was created.
+func (c Cerberus) UpsertContactByEmail(email string, ct Contact) (*Contact, bool, error) {
+	addr, err := c.FindOrCreateAddress(email)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if addr.ContactID != 0 {
+		ct.ID = addr.ContactID
+		updated, err := c.UpdateContact(ct)
+		return updated, false, err
+	}
+
+	ct.ID = 0
+	ct.EmailID = addr.ID
+	created, err := c.CreateContact(ct)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = c.SetAddressContact(addr.ID, created.ID)
+	if err != nil {
+		return nil, true, err
+	}
+
+	return created, true, nil
+}
@@ -0,0 +1,265 @@
+package cerb
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Messages in Cerb store their plain-text body in the message record and the HTML part as an attachment referenced by `html_attachment_id`, the same way Cerb's own email parser stores a multipart/alternative message. HTML supplied by customers is sanitized before it is uploaded so nothing executable ever reaches an agent's browser.
+
+// allowedHTMLTags are kept by SanitizeHTML. Any other tag is dropped but its inner text is kept.
+var allowedHTMLTags = map[string]bool{
+	"a": true, "abbr": true, "b": true, "blockquote": true, "br": true, "caption": true, "code": true,
+	"dd": true, "del": true, "div": true, "dl": true, "dt": true, "em": true, "font": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "hr": true,
+	"i": true, "img": true, "ins": true, "li": true, "ol": true, "p": true, "pre": true,
+	"s": true, "small": true, "span": true, "strike": true, "strong": true, "sub": true, "sup": true,
+	"table": true, "tbody": true, "td": true, "tfoot": true, "th": true, "thead": true, "tr": true,
+	"u": true, "ul": true,
+}
+
+// droppedHTMLTags are removed by SanitizeHTML along with everything inside them.
+var droppedHTMLTags = map[string]bool{
+	"applet": true, "embed": true, "form": true, "frame": true, "frameset": true, "head": true,
+	"iframe": true, "math": true, "noscript": true, "object": true, "script": true, "style": true,
+	"svg": true, "template": true, "textarea": true, "title": true,
+}
+
+// droppedHTMLClosingTags match the closing tag of each of the droppedHTMLTags
+var droppedHTMLClosingTags = func() map[string]*regexp.Regexp {
+	closing := map[string]*regexp.Regexp{}
+	for name := range droppedHTMLTags {
+		closing[name] = regexp.MustCompile(`(?i)</` + name + `\s*>`)
+	}
+	return closing
+}()
+
+// allowedHTMLAttributes are kept on any allowed tag. Event handlers (on*) and inline styles are always removed.
+var allowedHTMLAttributes = map[string]bool{
+	"align": true, "alt": true, "border": true, "cellpadding": true, "cellspacing": true, "color": true,
+	"colspan": true, "dir": true, "height": true, "href": true, "rowspan": true, "size": true,
+	"src": true, "title": true, "valign": true, "width": true,
+}
+
+// blockHTMLTags start a new line when converting HTML to plain text.
+var blockHTMLTags = map[string]bool{
+	"blockquote": true, "br": true, "div": true, "dl": true, "dt": true, "dd": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "hr": true,
+	"li": true, "ol": true, "p": true, "pre": true, "table": true, "tr": true, "ul": true,
+}
+
+var (
+	htmlTagPattern       = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9]*)((?:\s+[^\s"'>/=]+(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'>]+))?)*)\s*/?>`)
+	htmlAttributePattern = regexp.MustCompile(`([^\s"'>/=]+)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+)))?`)
+	safeURLPattern       = regexp.MustCompile(`^(?i)(https?:|mailto:|cid:|[^:]*$)`)
+	blankLinesPattern    = regexp.MustCompile(`\n{3,}`)
+	horizontalSpace      = regexp.MustCompile(`[ \t\f\v\r]+`)
+	whitespacePattern    = regexp.MustCompile(`\s+`)
+)
+
+// preformattedLine marks the lines HTMLToText writes within <pre> so their indentation survives the clean up of every other line
+const preformattedLine = "\x00"
+
+type htmlTokenKind int
+
+const (
+	htmlText htmlTokenKind = iota
+	htmlStartTag
+	htmlEndTag
+)
+
+type htmlToken struct {
+	kind  htmlTokenKind
+	text  string            // Raw text for htmlText tokens
+	name  string            // Lowercase tag name for tags
+	attrs map[string]string // Unescaped attribute values for start tags
+	order []string          // Attribute names in the order they appeared
+}
+
+// tokenizeHTML is a deliberately small HTML scanner: it understands tags, attributes and comments which is all we need to sanitize and flatten message bodies. Content within droppedHTMLTags is skipped entirely and a stray '<' that doesn't start a tag is returned as escaped text.
+func tokenizeHTML(s string, fn func(t htmlToken)) {
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '<')
+		if i < 0 {
+			fn(htmlToken{kind: htmlText, text: s})
+			return
+		}
+		if i > 0 {
+			fn(htmlToken{kind: htmlText, text: s[:i]})
+			s = s[i:]
+		}
+
+		if strings.HasPrefix(s, "<!--") {
+			end := strings.Index(s[4:], "-->")
+			if end < 0 {
+				return
+			}
+			s = s[4+end+3:]
+			continue
+		}
+
+		if strings.HasPrefix(s, "<!") || strings.HasPrefix(s, "<?") {
+			end := strings.IndexByte(s, '>')
+			if end < 0 {
+				return
+			}
+			s = s[end+1:]
+			continue
+		}
+
+		m := htmlTagPattern.FindStringSubmatch(s)
+		if m == nil {
+			fn(htmlToken{kind: htmlText, text: "&lt;"})
+			s = s[1:]
+			continue
+		}
+		s = s[len(m[0]):]
+
+		name := strings.ToLower(m[2])
+		if m[1] == "/" {
+			fn(htmlToken{kind: htmlEndTag, name: name})
+			continue
+		}
+
+		if droppedHTMLTags[name] {
+			loc := droppedHTMLClosingTags[name].FindStringIndex(s)
+			if loc == nil {
+				return
+			}
+			s = s[loc[1]:]
+			continue
+		}
+
+		t := htmlToken{kind: htmlStartTag, name: name, attrs: map[string]string{}}
+		for _, a := range htmlAttributePattern.FindAllStringSubmatch(m[3], -1) {
+			key := strings.ToLower(a[1])
+			if _, dup := t.attrs[key]; dup {
+				continue
+			}
+			t.attrs[key] = html.UnescapeString(a[2] + a[3] + a[4])
+			t.order = append(t.order, key)
+		}
+		fn(t)
+	}
+}
+
+// SanitizeHTML removes dangerous markup from the given HTML: scripts, styles, frames, forms, event handler attributes and non-http(s)/mailto URLs. Safe formatting tags are kept and everything else is reduced to its text.
+func SanitizeHTML(s string) string {
+	var b strings.Builder
+
+	tokenizeHTML(s, func(t htmlToken) {
+		switch t.kind {
+		case htmlText:
+			b.WriteString(strings.Replace(t.text, ">", "&gt;", -1))
+		case htmlEndTag:
+			if allowedHTMLTags[t.name] {
+				b.WriteString("</" + t.name + ">")
+			}
+		case htmlStartTag:
+			if !allowedHTMLTags[t.name] {
+				return
+			}
+			b.WriteString("<" + t.name)
+			for _, key := range t.order {
+				value := t.attrs[key]
+				if !allowedHTMLAttributes[key] {
+					continue
+				}
+				if (key == "href" || key == "src") && !safeURLPattern.MatchString(strings.TrimSpace(value)) {
+					continue
+				}
+				b.WriteString(" " + key + `="` + html.EscapeString(value) + `"`)
+			}
+			b.WriteString(">")
+		}
+	})
+
+	return b.String()
+}
+
+// HTMLToText converts HTML to a readable plain-text version suitable for the text body of a message. Block elements become line breaks, list items are bulleted and links keep their URL.
+func HTMLToText(s string) string {
+	var b strings.Builder
+	var href string
+	var linkText strings.Builder
+	inLink := false
+	inPre := false
+
+	write := func(text string) {
+		if inPre {
+			text = strings.Replace(text, "\n", "\n"+preformattedLine, -1)
+		}
+		if inLink {
+			linkText.WriteString(text)
+		} else {
+			b.WriteString(text)
+		}
+	}
+
+	tokenizeHTML(s, func(t htmlToken) {
+		switch t.kind {
+		case htmlText:
+			text := strings.Replace(html.UnescapeString(t.text), preformattedLine, "", -1)
+			if !inPre {
+				text = whitespacePattern.ReplaceAllString(text, " ")
+			}
+			write(text)
+		case htmlStartTag:
+			switch t.name {
+			case "a":
+				href = t.attrs["href"]
+				linkText.Reset()
+				inLink = true
+			case "li":
+				write("\n- ")
+			case "pre":
+				inPre = true
+				write("\n")
+			case "td", "th":
+				write("\t")
+			case "img":
+				if alt := t.attrs["alt"]; alt != "" {
+					write("[" + alt + "]")
+				}
+			default:
+				if blockHTMLTags[t.name] {
+					write("\n")
+				}
+			}
+		case htmlEndTag:
+			switch t.name {
+			case "a":
+				inLink = false
+				text := strings.TrimSpace(linkText.String())
+				b.WriteString(text)
+				if href != "" && href != text && !strings.HasPrefix(href, "#") {
+					b.WriteString(" <" + href + ">")
+				}
+			case "li":
+				// The next item or the end of the list starts the new line
+			case "pre":
+				inPre = false
+				write("\n")
+			case "p", "h1", "h2", "h3", "h4", "h5", "h6", "blockquote", "table", "ul", "ol":
+				write("\n\n")
+			default:
+				if blockHTMLTags[t.name] {
+					write("\n")
+				}
+			}
+		}
+	})
+
+	lines := strings.Split(b.String(), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, preformattedLine) {
+			lines[i] = strings.TrimRight(strings.TrimPrefix(line, preformattedLine), " \t\r")
+			continue
+		}
+		lines[i] = strings.TrimSpace(horizontalSpace.ReplaceAllString(line, " "))
+	}
+	text := blankLinesPattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+
+	return strings.Trim(text, "\n")
+}
@@ -0,0 +1,81 @@
+package cerb
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"keeps formatting", `<p>Hello <b>world</b></p>`, `<p>Hello <b>world</b></p>`},
+		{"drops script", `<p>a</p><script>alert(1)</script><p>b</p>`, `<p>a</p><p>b</p>`},
+		{"drops script case insensitively", `<SCRIPT>alert(1)</ScRiPt >ok`, `ok`},
+		{"drops style", `<style>p { color: red }</style><p>a</p>`, `<p>a</p>`},
+		{"drops unclosed script", `a<script>alert(1)`, `a`},
+		{"drops unclosed comment", `a<!-- <script>alert(1)</script>`, `a`},
+		{"drops comment", `a<!-- hidden -->b`, `ab`},
+		{"keeps text of unknown tags", `<article>text</article>`, `text`},
+		{"drops event handlers", `<img src="x.png" onerror="alert(1)" ONLOAD='alert(2)'>`, `<img src="x.png">`},
+		{"drops style attribute", `<p style="background:url(javascript:alert(1))">a</p>`, `<p>a</p>`},
+		{"keeps http links", `<a href="https://example.com/?a=1&amp;b=2">x</a>`, `<a href="https://example.com/?a=1&amp;b=2">x</a>`},
+		{"keeps mailto links", `<a href="mailto:a@example.com">x</a>`, `<a href="mailto:a@example.com">x</a>`},
+		{"keeps relative links", `<a href="/help">x</a>`, `<a href="/help">x</a>`},
+		{"drops javascript href", `<a href="javascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"drops padded javascript href", `<a href="  JavaScript:alert(1)">x</a>`, `<a>x</a>`},
+		{"drops entity encoded javascript href", `<a href="&#106;avascript&#58;alert(1)">x</a>`, `<a>x</a>`},
+		{"drops hex entity encoded javascript href", `<a href="javascript&#x3A;alert(1)">x</a>`, `<a>x</a>`},
+		{"drops javascript href with embedded whitespace", "<a href=\"java\tscript:alert(1)\">x</a>", `<a>x</a>`},
+		{"drops data src", `<img src="data:text/html;base64,PHNjcmlwdD4=">`, `<img>`},
+		{"escapes stray brackets", `1 < 2 > 0`, `1 &lt; 2 &gt; 0`},
+		{"escapes unclosed tag", `<a href="x" onclick="alert(1)"`, `&lt;a href="x" onclick="alert(1)"`},
+		{"escapes quotes in attributes", `<img alt='a"><script>alert(1)</script>'>`, `<img alt="a&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;">`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SanitizeHTML(tt.in)
+			if got != tt.want {
+				t.Errorf("SanitizeHTML(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+			if strings.Contains(strings.ToLower(got), "<script") {
+				t.Errorf("SanitizeHTML(%q) kept a script tag: %q", tt.in, got)
+			}
+		})
+	}
+}
+
+func TestHTMLToText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"paragraphs", `<p>One</p><p>Two</p>`, "One\n\nTwo"},
+		{"line breaks", `a<br>b<br/>c`, "a\nb\nc"},
+		{"collapses whitespace", "<p>  lots   of\n space </p>", "lots of space"},
+		{"keeps preformatted line breaks", "<pre>a\nb\n\nc</pre>", "a\nb\n\nc"},
+		{"keeps preformatted indentation", "<p>Code:</p><pre>if x {\n    return 1\n}</pre><p>done</p>", "Code:\n\nif x {\n    return 1\n}\n\ndone"},
+		{"keeps indentation of a leading pre", "<pre>    indented\n\tand tabbed</pre>", "    indented\n\tand tabbed"},
+		{"line breaks within pre", "<pre>a<br>  b</pre>", "a\n  b"},
+		{"lists", `<ul><li>one</li><li>two</li></ul>`, "- one\n- two"},
+		{"links", `<a href="https://example.com">site</a>`, "site <https://example.com>"},
+		{"links to themselves", `<a href="https://example.com">https://example.com</a>`, "https://example.com"},
+		{"images", `<img alt="logo">`, "[logo]"},
+		{"entities", `Tom &amp; Jerry &lt;3`, "Tom & Jerry <3"},
+		{"skips scripts", `a<script>alert(1)</script>b`, "ab"},
+		{"plain text", "just text", "just text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := HTMLToText(tt.in)
+			if got != tt.want {
+				t.Errorf("HTMLToText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
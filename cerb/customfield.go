@@ -0,0 +1,59 @@
+package cerb
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// CustomFieldDefinition describes a custom field that records of a given context can have. Use its ID when setting a CustomField. @see https://cerb.ai/docs/records/types/custom_field/
+type CustomFieldDefinition struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`    // e.g. [S]ingle line, [T]ext, [N]umber, [C]heckbox, [D]ropdown, [E]date
+	Context    string `json:"context"` // e.g. cerberusweb.contexts.ticket
+	FieldsetID int    `json:"custom_fieldset_id"`
+	Position   int    `json:"pos"`
+	URL        string `json:"record_url"`
+	Updated    int    `json:"updated_at"`
+}
+
+// SearchCustomFieldsResponse is the response from the records/custom_field/search.json endpoint
+type SearchCustomFieldsResponse struct {
+	Status  string                  `json:"__status"`
+	Count   int                     `json:"count"`
+	Limit   int                     `json:"limit"`
+	Page    int                     `json:"page"`
+	Results []CustomFieldDefinition `json:"results"`
+	Total   int                     `json:"total"`
+	Version string                  `json:"__version"`
+}
+
+// FindAllCustomFields lists the custom field definitions for every record type, following Cerb's pagination
+func (c Cerberus) FindAllCustomFields(opts ...SearchOptions) (*[]CustomFieldDefinition, error) {
+	limit := 250 // Maximum of 250 enforced by server
+	params := url.Values{}
+	params.Set("q", "")
+	params.Set("limit", strconv.Itoa(limit))
+	applySearchOptions(params, opts)
+
+	fields := []CustomFieldDefinition{}
+	for page := 0; ; page++ {
+		params.Set("page", strconv.Itoa(page))
+
+		var r SearchCustomFieldsResponse
+		err := c.performRequest(http.MethodGet, "records/custom_field/search.json", params, nil, &r)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to search custom fields on page %d: %v", page, err)
+		}
+
+		fields = append(fields, r.Results...)
+		if len(r.Results) == 0 || len(fields) >= r.Total {
+			break
+		}
+	}
+
+	return &fields, nil
+}
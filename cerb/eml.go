@@ -0,0 +1,192 @@
+package cerb
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/http"
+	"net/mail"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseMessageResponse is the response from the parser/parse.json endpoint
+type ParseMessageResponse struct {
+	MessageID int    `json:"message_id"`
+	TicketID  int    `json:"ticket_id"`
+	Mask      string `json:"mask"`
+}
+
+// ImportRawMessage hands a raw RFC 822 message (e.g. the contents of an .eml file) to Cerb's email parser. Cerb routes it exactly as if it had arrived by email so the original headers, threading (In-Reply-To/References) and attachments are all kept.
+func (c Cerberus) ImportRawMessage(raw []byte) (*ParseMessageResponse, error) {
+	form := url.Values{}
+	form.Set("message", string(raw))
+
+	var r ParseMessageResponse
+	err := c.performRequest(http.MethodPost, "parser/parse.json", nil, form, &r)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to import raw message: %v", err)
+	}
+
+	return &r, nil
+}
+
+// CustomerQuestionFromMail converts a parsed email into a CustomerQuestion so it can be created with CreateMessage. The plain-text and HTML parts become Content and HTMLContent and every other part becomes an attachment. Routing fields (GroupID, BucketID, etc) are left for the caller to fill in.
+//
+// Unlike ImportRawMessage the original headers are not kept, so prefer that when threading matters. Text parts must be UTF-8, US-ASCII, ISO-8859-1 or Windows-1252; any other charset is an error and ImportRawMessage should be used instead.
+func CustomerQuestionFromMail(m *mail.Message) (*CustomerQuestion, error) {
+	dec := new(mime.WordDecoder)
+
+	subject, err := dec.DecodeHeader(m.Header.Get("Subject"))
+	if err != nil {
+		subject = m.Header.Get("Subject")
+	}
+
+	from, err := m.Header.AddressList("From")
+	if err != nil || len(from) == 0 {
+		return nil, fmt.Errorf("Message has no valid From address: %v", err)
+	}
+
+	q := CustomerQuestion{
+		From:    from[0].Address,
+		Subject: subject,
+	}
+
+	if to, err := m.Header.AddressList("To"); err == nil && len(to) > 0 {
+		q.To = to[0].Address
+	}
+
+	if cc, err := m.Header.AddressList("Cc"); err == nil {
+		for _, a := range cc {
+			q.Participants = append(q.Participants, a.Address)
+		}
+	}
+
+	header := textproto.MIMEHeader(m.Header)
+	err = readMIMEPart(header, m.Body, &q, dec)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read message body: %v", err)
+	}
+
+	return &q, nil
+}
+
+// readMIMEPart walks a (possibly nested) MIME part and fills in the content and attachments of q
+func readMIMEPart(header textproto.MIMEHeader, body io.Reader, q *CustomerQuestion, dec *mime.WordDecoder) error {
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "text/plain"
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "application/octet-stream"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+
+		for {
+			p, err := mr.NextPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("Error reading %s part: %v", mediaType, err)
+			}
+
+			err = readMIMEPart(p.Header, p, q, dec)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := ioutil.ReadAll(decodeTransferEncoding(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return fmt.Errorf("Error decoding %s part: %v", mediaType, err)
+	}
+
+	disposition, dparams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := dparams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	// Mail clients commonly encode non-ASCII names as RFC 2047 words, e.g. =?UTF-8?B?...?=, rather than RFC 2231 parameters
+	if decoded, err := dec.DecodeHeader(filename); err == nil {
+		filename = decoded
+	}
+
+	if disposition != "attachment" && filename == "" {
+		switch {
+		case mediaType == "text/plain" && q.Content == "":
+			q.Content, err = decodeCharset(data, params["charset"])
+			return err
+		case mediaType == "text/html" && q.HTMLContent == "":
+			q.HTMLContent, err = decodeCharset(data, params["charset"])
+			return err
+		}
+	}
+
+	if filename == "" {
+		filename = "attachment"
+		if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+			filename += exts[0]
+		}
+	}
+
+	q.Attachments = append(q.Attachments, AttachmentFile{
+		Name:     filename,
+		MimeType: mediaType,
+		Data:     data,
+	})
+
+	return nil
+}
+
+func decodeTransferEncoding(encoding string, r io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, r) // Line breaks are ignored by the decoder
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	}
+	return r
+}
+
+// windows1252 maps the bytes 0x80-0x9F of Windows-1252, where it differs from Latin-1. Unassigned bytes are left as their Latin-1 control characters.
+var windows1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+}
+
+// decodeCharset converts text to UTF-8. Only the charsets the standard library can get by without (UTF-8, US-ASCII, Latin-1 and Windows-1252) are supported; anything else is an error rather than silently garbled text. Invalid UTF-8 is replaced with U+FFFD.
+func decodeCharset(data []byte, charset string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(charset)) {
+	case "", "utf-8", "utf8", "us-ascii", "ascii":
+		return strings.ToValidUTF8(string(data), string(utf8.RuneError)), nil
+	case "iso-8859-1", "latin1", "latin-1":
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return string(runes), nil
+	case "windows-1252", "cp1252":
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+			if b >= 0x80 && b <= 0x9F {
+				runes[i] = windows1252[b-0x80]
+			}
+		}
+		return string(runes), nil
+	}
+	return "", fmt.Errorf("Unsupported charset %q", charset)
+}
@@ -0,0 +1,86 @@
+package cerb
+
+import (
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestCustomerQuestionFromMail(t *testing.T) {
+	raw := strings.Join([]string{
+		"From: Jane Doe <jane@example.com>",
+		"To: support@example.com",
+		"Subject: =?UTF-8?B?R3LDvMOfZQ==?=",
+		"MIME-Version: 1.0",
+		`Content-Type: multipart/mixed; boundary="b1"`,
+		"",
+		"--b1",
+		"Content-Type: text/plain; charset=windows-1252",
+		"Content-Transfer-Encoding: quoted-printable",
+		"",
+		"=93Quoted=94 =80 caf=E9",
+		"--b1",
+		`Content-Type: application/pdf; name="=?UTF-8?Q?Rechnung_M=C3=A4rz.pdf?="`,
+		`Content-Disposition: attachment; filename="=?UTF-8?Q?Rechnung_M=C3=A4rz.pdf?="`,
+		"Content-Transfer-Encoding: base64",
+		"",
+		"JVBERi0=",
+		"--b1--",
+		"",
+	}, "\r\n")
+
+	m, err := mail.ReadMessage(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := CustomerQuestionFromMail(m)
+	if err != nil {
+		t.Fatalf("CustomerQuestionFromMail() error = %v", err)
+	}
+
+	if q.From != "jane@example.com" || q.To != "support@example.com" {
+		t.Errorf("From, To = %q, %q", q.From, q.To)
+	}
+	if q.Subject != "Grüße" {
+		t.Errorf("Subject = %q, want %q", q.Subject, "Grüße")
+	}
+	if want := "“Quoted” € café"; q.Content != want {
+		t.Errorf("Content = %q, want %q", q.Content, want)
+	}
+	if len(q.Attachments) != 1 {
+		t.Fatalf("got %d attachments, want 1", len(q.Attachments))
+	}
+	if a := q.Attachments[0]; a.Name != "Rechnung März.pdf" || a.MimeType != "application/pdf" || string(a.Data) != "%PDF-" {
+		t.Errorf("attachment = %q %q %q", a.Name, a.MimeType, a.Data)
+	}
+}
+
+func TestDecodeCharset(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		charset string
+		want    string
+		wantErr bool
+	}{
+		{"utf-8", "café", "UTF-8", "café", false},
+		{"no charset is utf-8", "café", "", "café", false},
+		{"invalid utf-8 is replaced", "caf\xe9", "utf-8", "caf�", false},
+		{"latin-1", "caf\xe9", "ISO-8859-1", "café", false},
+		{"windows-1252", "\x93hi\x94 \x80", "windows-1252", "“hi” €", false},
+		{"unknown charset", "\x82\xa0", "shift_jis", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCharset([]byte(tt.data), tt.charset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeCharset() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("decodeCharset() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package cerb
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Every change here invalidates the cached groups and buckets (CacheKeyGroups) so routing picks it up straight away.
+
+// Group membership roles used by the `members` field of group records
+const (
+	groupRoleRemove  = 0
+	groupRoleMember  = 1
+	groupRoleManager = 2
+)
+
+// CreateGroup creates a new group. Cerb creates an "Inbox" bucket in every new group which becomes its default bucket.
+func (c Cerberus) CreateGroup(name string) (*Group, error) {
+	form := url.Values{}
+	form.Set("fields[name]", name)
+
+	var g Group
+	err := c.performRequest(http.MethodPost, "records/group/create.json", nil, form, &g)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create group %s: %v", name, err)
+	}
+
+	c.InvalidateCache(CacheKeyGroups)
+	return &g, nil
+}
+
+// RenameGroup changes the name of the given group
+func (c Cerberus) RenameGroup(groupID int, name string) (*Group, error) {
+	form := url.Values{}
+	form.Set("fields[name]", name)
+
+	var g Group
+	err := c.performRequest(http.MethodPut, "records/group/"+strconv.Itoa(groupID)+".json", nil, form, &g)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to rename group %d to %s: %v", groupID, name, err)
+	}
+
+	c.InvalidateCache(CacheKeyGroups)
+	return &g, nil
+}
+
+// DeleteGroup permanently deletes the given group along with its buckets
+func (c Cerberus) DeleteGroup(groupID int) error {
+	err := c.deleteRecord("group", groupID)
+	if err != nil {
+		return err
+	}
+
+	c.InvalidateCache(CacheKeyGroups)
+	return nil
+}
+
+// AddGroupMember adds the worker to the group, optionally as a manager. Calling it for an existing member changes their role.
+func (c Cerberus) AddGroupMember(groupID int, workerID int, manager bool) error {
+	role := groupRoleMember
+	if manager {
+		role = groupRoleManager
+	}
+
+	return c.setGroupMemberRole(groupID, workerID, role)
+}
+
+// RemoveGroupMember removes the worker from the group
+func (c Cerberus) RemoveGroupMember(groupID int, workerID int) error {
+	return c.setGroupMemberRole(groupID, workerID, groupRoleRemove)
+}
+
+func (c Cerberus) setGroupMemberRole(groupID int, workerID int, role int) error {
+	form := url.Values{}
+	form.Set("fields[members]["+strconv.Itoa(workerID)+"]", strconv.Itoa(role))
+
+	var g Group
+	err := c.performRequest(http.MethodPut, "records/group/"+strconv.Itoa(groupID)+".json", nil, form, &g)
+
+	if err != nil {
+		return fmt.Errorf("Failed to set role of worker %d in group %d: %v", workerID, groupID, err)
+	}
+
+	c.InvalidateCache(CacheKeyGroups, CacheKeyWorkers)
+	return nil
+}
+
+// CreateBucket creates a new bucket within the given group
+func (c Cerberus) CreateBucket(groupID int, name string) (*Bucket, error) {
+	form := url.Values{}
+	form.Set("expand", "group_")
+	form.Set("fields[group_id]", strconv.Itoa(groupID))
+	form.Set("fields[name]", name)
+
+	var b Bucket
+	err := c.performRequest(http.MethodPost, "records/bucket/create.json", nil, form, &b)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create bucket %s in group %d: %v", name, groupID, err)
+	}
+
+	c.InvalidateCache(CacheKeyGroups)
+	return &b, nil
+}
+
+// RenameBucket changes the name of the given bucket
+func (c Cerberus) RenameBucket(bucketID int, name string) (*Bucket, error) {
+	form := url.Values{}
+	form.Set("expand", "group_")
+	form.Set("fields[name]", name)
+
+	var b Bucket
+	err := c.performRequest(http.MethodPut, "records/bucket/"+strconv.Itoa(bucketID)+".json", nil, form, &b)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to rename bucket %d to %s: %v", bucketID, name, err)
+	}
+
+	c.InvalidateCache(CacheKeyGroups)
+	return &b, nil
+}
+
+// DeleteBucket permanently deletes the given bucket. Cerb moves its tickets to the group's default bucket.
+func (c Cerberus) DeleteBucket(bucketID int) error {
+	err := c.deleteRecord("bucket", bucketID)
+	if err != nil {
+		return err
+	}
+
+	c.InvalidateCache(CacheKeyGroups)
+	return nil
+}
+
+// SetDefaultBucket makes the bucket the default for its group. New tickets routed to the group without a bucket land there.
+func (c Cerberus) SetDefaultBucket(bucketID int) error {
+	form := url.Values{}
+	form.Set("fields[is_default]", "1")
+
+	var b Bucket
+	err := c.performRequest(http.MethodPut, "records/bucket/"+strconv.Itoa(bucketID)+".json", nil, form, &b)
+
+	if err != nil {
+		return fmt.Errorf("Failed to make bucket %d the default: %v", bucketID, err)
+	}
+
+	c.InvalidateCache(CacheKeyGroups)
+	return nil
+}
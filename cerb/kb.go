@@ -0,0 +1,292 @@
+package cerb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Formats of knowledgebase article content
+const (
+	KBFormatPlainText = 0
+	KBFormatHTML      = 1
+	KBFormatMarkdown  = 2
+)
+
+// KBArticle represents a knowledgebase article. @see https://cerb.ai/docs/records/types/kb_article/
+type KBArticle struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Content     string `json:"content"`
+	Format      int    `json:"format"` // One of the KBFormat constants
+	Views       int    `json:"views"`
+	URL         string `json:"record_url"`
+	Updated     int    `json:"updated"`
+	CategoryIDs []int  `json:"-"` // Categories the article is filed in. Set when creating or updating to refile it.
+}
+
+// UnmarshalJSON decodes the article along with its expanded `categories`, which Cerb returns as a list of IDs or as category records keyed by ID
+func (a *KBArticle) UnmarshalJSON(data []byte) error {
+	type article KBArticle // Without the UnmarshalJSON method, so decoding it doesn't recurse
+	var r struct {
+		article
+		Categories json.RawMessage `json:"categories"`
+	}
+	err := json.Unmarshal(data, &r)
+	if err != nil {
+		return err
+	}
+
+	*a = KBArticle(r.article)
+	a.CategoryIDs, err = kbCategoryIDs(r.Categories)
+	return err
+}
+
+// kbCategoryIDs reads the IDs from an article's expanded categories
+func kbCategoryIDs(raw json.RawMessage) ([]int, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var numbers []json.Number
+	if json.Unmarshal(raw, &numbers) == nil {
+		ids := make([]int, len(numbers))
+		for i, n := range numbers {
+			id, err := strconv.Atoi(n.String())
+			if err != nil {
+				return nil, fmt.Errorf("Unexpected kb category ID %q: %v", n, err)
+			}
+			ids[i] = id
+		}
+		return ids, nil
+	}
+
+	var categories []KBCategory
+	err := decodeExpandedRecords(raw, &categories)
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding kb categories: %v", err)
+	}
+
+	ids := make([]int, len(categories))
+	for i, cat := range categories {
+		ids[i] = cat.ID
+	}
+	return ids, nil
+}
+
+// KBCategory represents a knowledgebase category. Categories are nested; top-level categories have a ParentID of 0. @see https://cerb.ai/docs/records/types/kb_category/
+type KBCategory struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	ParentID int    `json:"parent_id"`
+	URL      string `json:"record_url"`
+	Updated  int    `json:"updated_at"`
+}
+
+// SearchKBArticlesResponse is the response from the records/kb_article/search.json endpoint
+type SearchKBArticlesResponse struct {
+	Status  string      `json:"__status"`
+	Count   int         `json:"count"`
+	Limit   int         `json:"limit"`
+	Page    int         `json:"page"`
+	Results []KBArticle `json:"results"`
+	Total   int         `json:"total"`
+	Version string      `json:"__version"`
+}
+
+// SearchKBCategoriesResponse is the response from the records/kb_category/search.json endpoint
+type SearchKBCategoriesResponse struct {
+	Status  string       `json:"__status"`
+	Count   int          `json:"count"`
+	Limit   int          `json:"limit"`
+	Page    int          `json:"page"`
+	Results []KBCategory `json:"results"`
+	Total   int          `json:"total"`
+	Version string       `json:"__version"`
+}
+
+// SearchKBArticles finds articles matching the given Cerb search query, e.g. `title:"*vault*"` or `category.id:3`
+func (c Cerberus) SearchKBArticles(query string, opts ...SearchOptions) (*[]KBArticle, error) {
+	limit := 250 // If you need pagination imitate ListOpenTickets
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(limit))
+	params.Set("expand", "content,categories")
+	applySearchOptions(params, opts)
+
+	var r SearchKBArticlesResponse
+	err := c.performRequest(http.MethodGet, "records/kb_article/search.json", params, nil, &r)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to search kb articles: %v", err)
+	}
+
+	return &r.Results, nil
+}
+
+// GetKBArticle loads the article with the given ID, including its content and categories
+func (c Cerberus) GetKBArticle(articleID int) (*KBArticle, error) {
+	params := url.Values{}
+	params.Set("expand", "content,categories")
+
+	var a KBArticle
+	err := c.performRequest(http.MethodGet, "records/kb_article/"+strconv.Itoa(articleID)+".json", params, nil, &a)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get kb article %d: %v", articleID, err)
+	}
+
+	return &a, nil
+}
+
+// kbArticleForm converts the non-zero fields of a into the form used by the create and update endpoints. Plain text is the zero value of Format so it's only sent when creating.
+func kbArticleForm(a KBArticle, create bool) url.Values {
+	form := url.Values{}
+
+	if create || a.Format != KBFormatPlainText {
+		form.Set("fields[format]", strconv.Itoa(a.Format))
+	}
+	if a.Title != "" {
+		form.Set("fields[title]", a.Title)
+	}
+	if a.Content != "" {
+		form.Set("fields[content]", a.Content)
+	}
+	for _, id := range a.CategoryIDs {
+		form.Add("fields[categories][]", strconv.Itoa(id))
+	}
+
+	return form
+}
+
+// CreateKBArticle publishes a new article. Markdown content should use KBFormatMarkdown so Cerb renders it.
+func (c Cerberus) CreateKBArticle(a KBArticle) (*KBArticle, error) {
+	var created KBArticle
+	err := c.performRequest(http.MethodPost, "records/kb_article/create.json", nil, kbArticleForm(a, true), &created)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create kb article %s: %v", a.Title, err)
+	}
+
+	return &created, nil
+}
+
+// UpdateKBArticle updates the article with the non-zero fields of a. a.ID must be set. Use SetKBArticleFormat to convert an article to plain text.
+func (c Cerberus) UpdateKBArticle(a KBArticle) (*KBArticle, error) {
+	var updated KBArticle
+	err := c.performRequest(http.MethodPut, "records/kb_article/"+strconv.Itoa(a.ID)+".json", nil, kbArticleForm(a, false), &updated)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update kb article %d: %v", a.ID, err)
+	}
+
+	return &updated, nil
+}
+
+// SetKBArticleFormat changes the format of the article's content to one of the KBFormat constants
+func (c Cerberus) SetKBArticleFormat(articleID int, format int) error {
+	form := url.Values{}
+	form.Set("fields[format]", strconv.Itoa(format))
+
+	var r recordStatusResponse
+	err := c.performRequest(http.MethodPut, "records/kb_article/"+strconv.Itoa(articleID)+".json", nil, form, &r)
+
+	if err != nil {
+		return fmt.Errorf("Failed to set format of kb article %d: %v", articleID, err)
+	}
+
+	return nil
+}
+
+// SearchKBCategories finds categories matching the given Cerb search query, following Cerb's pagination. An empty query returns every category.
+func (c Cerberus) SearchKBCategories(query string, opts ...SearchOptions) (*[]KBCategory, error) {
+	limit := 250 // Maximum of 250 enforced by server
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(limit))
+	applySearchOptions(params, opts)
+
+	categories := []KBCategory{}
+	for page := 0; ; page++ {
+		params.Set("page", strconv.Itoa(page))
+
+		var r SearchKBCategoriesResponse
+		err := c.performRequest(http.MethodGet, "records/kb_category/search.json", params, nil, &r)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to search kb categories on page %d: %v", page, err)
+		}
+
+		categories = append(categories, r.Results...)
+		if len(r.Results) == 0 || len(categories) >= r.Total {
+			break
+		}
+	}
+
+	return &categories, nil
+}
+
+// GetKBCategory loads the category with the given ID
+func (c Cerberus) GetKBCategory(categoryID int) (*KBCategory, error) {
+	var cat KBCategory
+	err := c.performRequest(http.MethodGet, "records/kb_category/"+strconv.Itoa(categoryID)+".json", nil, nil, &cat)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get kb category %d: %v", categoryID, err)
+	}
+
+	return &cat, nil
+}
+
+// CreateKBCategory creates a new category. Use a parentID of 0 for a top-level category.
+func (c Cerberus) CreateKBCategory(name string, parentID int) (*KBCategory, error) {
+	form := url.Values{}
+	form.Set("fields[name]", name)
+	form.Set("fields[parent_id]", strconv.Itoa(parentID))
+
+	var cat KBCategory
+	err := c.performRequest(http.MethodPost, "records/kb_category/create.json", nil, form, &cat)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create kb category %s: %v", name, err)
+	}
+
+	return &cat, nil
+}
+
+// UpdateKBCategory renames the category and, when cat.ParentID is set, moves it under that parent. Use MoveKBCategory to move a category to the top level.
+func (c Cerberus) UpdateKBCategory(cat KBCategory) (*KBCategory, error) {
+	form := url.Values{}
+	if cat.Name != "" {
+		form.Set("fields[name]", cat.Name)
+	}
+	if cat.ParentID != 0 {
+		form.Set("fields[parent_id]", strconv.Itoa(cat.ParentID))
+	}
+
+	var updated KBCategory
+	err := c.performRequest(http.MethodPut, "records/kb_category/"+strconv.Itoa(cat.ID)+".json", nil, form, &updated)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update kb category %d: %v", cat.ID, err)
+	}
+
+	return &updated, nil
+}
+
+// MoveKBCategory moves the category under parentID, or to the top level when parentID is 0
+func (c Cerberus) MoveKBCategory(categoryID int, parentID int) error {
+	form := url.Values{}
+	form.Set("fields[parent_id]", strconv.Itoa(parentID))
+
+	var r recordStatusResponse
+	err := c.performRequest(http.MethodPut, "records/kb_category/"+strconv.Itoa(categoryID)+".json", nil, form, &r)
+
+	if err != nil {
+		return fmt.Errorf("Failed to move kb category %d to parent %d: %v", categoryID, parentID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package cerb
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestKBArticleUnmarshalCategories(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want []int
+	}{
+		{"not expanded", `{"id":1,"title":"Vault"}`, nil},
+		{"empty list", `{"id":1,"title":"Vault","categories":[]}`, []int{}},
+		{"list of IDs", `{"id":1,"title":"Vault","categories":[4,"2"]}`, []int{4, 2}},
+		{"records keyed by ID", `{"id":1,"title":"Vault","categories":{"9":{"id":9,"name":"B"},"3":{"id":3,"name":"A"}}}`, []int{3, 9}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a KBArticle
+			err := json.Unmarshal([]byte(tt.json), &a)
+			if err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if a.ID != 1 || a.Title != "Vault" {
+				t.Errorf("Unmarshal() article = %+v", a)
+			}
+			if !reflect.DeepEqual(a.CategoryIDs, tt.want) {
+				t.Errorf("CategoryIDs = %v, want %v", a.CategoryIDs, tt.want)
+			}
+		})
+	}
+}
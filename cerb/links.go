@@ -0,0 +1,125 @@
+package cerb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Any two Cerb records can be linked (ticket ↔ task, ticket ↔ org, ticket ↔ ticket, ...). Links are bidirectional and are managed through the `links` field of either record: `context:id` adds a link and `-context:id` removes it.
+
+// RecordRef identifies a record by its context alias (e.g. "ticket", "task", "org") and ID
+type RecordRef struct {
+	Context string
+	ID      int
+}
+
+func (r RecordRef) String() string {
+	return r.Context + ":" + strconv.Itoa(r.ID)
+}
+
+// LinkedRecord is a record returned by ListLinks. Use Decode to expand it into its full type, e.g. a Task or CerberusTicket.
+type LinkedRecord struct {
+	Context string
+	ID      int
+	Label   string
+	URL     string
+
+	raw json.RawMessage
+}
+
+// Ref returns the RecordRef for the linked record
+func (l LinkedRecord) Ref() RecordRef {
+	return RecordRef{Context: l.Context, ID: l.ID}
+}
+
+// Decode unmarshals the full linked record into target, e.g. a *Task when the link was listed with the "task" context
+func (l LinkedRecord) Decode(target interface{}) error {
+	err := json.Unmarshal(l.raw, target)
+
+	if err != nil {
+		return fmt.Errorf("Error decoding linked %s %d: %v", l.Context, l.ID, err)
+	}
+
+	return nil
+}
+
+// linkedRecordSearchResults is the raw structure returned by records/{context}/search.json when listing links
+type linkedRecordSearchResults struct {
+	Results []json.RawMessage `json:"results"`
+	Total   int               `json:"total"`
+}
+
+// LinkRecords links the two records
+func (c Cerberus) LinkRecords(from RecordRef, to RecordRef) error {
+	return c.updateLinks(from, to.String())
+}
+
+// UnlinkRecords removes the link between the two records
+func (c Cerberus) UnlinkRecords(from RecordRef, to RecordRef) error {
+	return c.updateLinks(from, "-"+to.String())
+}
+
+func (c Cerberus) updateLinks(from RecordRef, link string) error {
+	form := url.Values{}
+	form.Add("fields[links][]", link)
+
+	var r recordStatusResponse
+	err := c.performRequest(http.MethodPut, "records/"+from.Context+"/"+strconv.Itoa(from.ID)+".json", nil, form, &r)
+
+	if err != nil {
+		return fmt.Errorf("Failed to update links of %s with %s: %v", from, link, err)
+	}
+
+	return nil
+}
+
+// ListLinks lists the records of toContext (e.g. "task") that are linked to the from record
+func (c Cerberus) ListLinks(from RecordRef, toContext string, opts ...SearchOptions) (*[]LinkedRecord, error) {
+	limit := 250 // Maximum of 250 enforced by server
+	params := url.Values{}
+	params.Set("q", "links."+from.Context+":(id:"+strconv.Itoa(from.ID)+")")
+	params.Set("limit", strconv.Itoa(limit))
+	applySearchOptions(params, opts)
+
+	links := []LinkedRecord{}
+	for page := 0; ; page++ {
+		params.Set("page", strconv.Itoa(page))
+
+		var r linkedRecordSearchResults
+		err := c.performRequest(http.MethodGet, "records/"+toContext+"/search.json", params, nil, &r)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to list %s links of %s: %v", toContext, from, err)
+		}
+
+		for _, raw := range r.Results {
+			var summary struct {
+				ID    int    `json:"id"`
+				Label string `json:"_label"`
+				URL   string `json:"record_url"`
+			}
+
+			err = json.Unmarshal(raw, &summary)
+			if err != nil {
+				return nil, fmt.Errorf("Error decoding linked %s: %v", toContext, err)
+			}
+
+			links = append(links, LinkedRecord{
+				Context: toContext,
+				ID:      summary.ID,
+				Label:   summary.Label,
+				URL:     summary.URL,
+				raw:     raw,
+			})
+		}
+
+		if len(r.Results) == 0 || len(links) >= r.Total {
+			break
+		}
+	}
+
+	return &links, nil
+}
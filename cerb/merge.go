@@ -0,0 +1,153 @@
+package cerb
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// MergeResult describes the ticket that survived a merge
+type MergeResult struct {
+	TicketID    int      // The surviving ticket
+	Mask        string   // Mask of the surviving ticket
+	MergedIDs   []int    // Tickets merged into the surviving ticket
+	MergedMasks []string // Masks of the merged tickets. Cerb keeps them as aliases that forward to the surviving ticket.
+}
+
+// SplitResult describes the ticket created by splitting a message out of its ticket
+type SplitResult struct {
+	TicketID         int    // The new ticket containing the message
+	Mask             string // Mask of the new ticket
+	OriginalTicketID int    // The ticket the message was split from
+}
+
+// MergeTickets merges the source tickets into the target ticket using Cerb's merge endpoint. Duplicate source IDs are ignored. Messages, comments and participants are moved to the target and the source masks become aliases of it, so replies to the old masks still thread correctly.
+func (c Cerberus) MergeTickets(targetID int, sourceIDs ...int) (*MergeResult, error) {
+	if len(sourceIDs) == 0 {
+		return nil, fmt.Errorf("No tickets given to merge into ticket %d", targetID)
+	}
+
+	result := MergeResult{}
+	ids := []string{}
+	seen := map[int]bool{}
+
+	for _, id := range sourceIDs {
+		if id == targetID {
+			return nil, fmt.Errorf("Can't merge ticket %d into itself", id)
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		t, err := c.GetTicket(id)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load ticket %d to merge: %v", id, err)
+		}
+
+		ids = append(ids, strconv.Itoa(id))
+		result.MergedIDs = append(result.MergedIDs, t.ID)
+		result.MergedMasks = append(result.MergedMasks, t.Mask)
+	}
+
+	form := url.Values{}
+	form.Set("target_id", strconv.Itoa(targetID))
+	form.Set("ids", strings.Join(ids, ","))
+
+	var r recordStatusResponse
+	err := c.performRequest(http.MethodPost, "tickets/merge.json", nil, form, &r)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to merge tickets %s into ticket %d: %v", form.Get("ids"), targetID, err)
+	}
+
+	target, err := c.GetTicket(targetID)
+	if err != nil {
+		return nil, fmt.Errorf("Tickets merged but failed to load the surviving ticket %d: %v", targetID, err)
+	}
+
+	result.TicketID = target.ID
+	result.Mask = target.Mask
+
+	return &result, nil
+}
+
+// SplitMessage moves a message out of its ticket into a new ticket in the same group and bucket, with the same subject, status and participants. If the message can't be moved the new ticket is deleted again.
+func (c Cerberus) SplitMessage(messageID int) (*SplitResult, error) {
+	var message struct {
+		TicketID int `json:"ticket_id"`
+	}
+	err := c.performRequest(http.MethodGet, "records/message/"+strconv.Itoa(messageID)+".json", nil, nil, &message)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load message %d to split: %v", messageID, err)
+	}
+
+	original, err := c.GetTicket(message.TicketID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load ticket of message %d: %v", messageID, err)
+	}
+
+	participants, err := c.ListTicketParticipants(original.ID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load participants of ticket %d to split: %v", original.ID, err)
+	}
+
+	form := url.Values{}
+	form.Set("fields[group_id]", strconv.Itoa(original.GroupID))
+	form.Set("fields[bucket_id]", strconv.Itoa(original.BucketID))
+	form.Set("fields[status]", ticketStatusCode(original.Status))
+	form.Set("fields[subject]", original.Subject)
+
+	var ticket CreateTicketResponse
+	err = c.performRequest(http.MethodPost, "records/ticket/create.json", nil, form, &ticket)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create ticket to split message %d into: %v", messageID, err)
+	}
+
+	// rollback deletes the new ticket so a failed split doesn't leave an empty ticket behind
+	rollback := func(err error) (*SplitResult, error) {
+		if delErr := c.DeleteTicket(ticket.ID); delErr != nil {
+			return nil, fmt.Errorf("%v (and failed to delete the new ticket %d: %v)", err, ticket.ID, delErr)
+		}
+		return nil, err
+	}
+
+	emails := make([]string, len(*participants))
+	for i, a := range *participants {
+		emails[i] = a.Email
+	}
+	if len(emails) > 0 {
+		_, err = c.AddTicketParticipants(ticket.ID, emails...)
+		if err != nil {
+			return rollback(fmt.Errorf("Failed to copy participants of ticket %d to ticket %d: %v", original.ID, ticket.ID, err))
+		}
+	}
+
+	form = url.Values{}
+	form.Set("fields[ticket_id]", strconv.Itoa(ticket.ID))
+
+	var r recordStatusResponse
+	err = c.performRequest(http.MethodPut, "records/message/"+strconv.Itoa(messageID)+".json", nil, form, &r)
+
+	if err != nil {
+		return rollback(fmt.Errorf("Failed to move message %d to ticket %d: %v", messageID, ticket.ID, err))
+	}
+
+	return &SplitResult{
+		TicketID:         ticket.ID,
+		Mask:             ticket.Mask,
+		OriginalTicketID: original.ID,
+	}, nil
+}
+
+// ticketStatusCode converts a ticket status as returned by Cerb (e.g. "open") to the single letter used when setting it (e.g. "o")
+func ticketStatusCode(status string) string {
+	if status == "" {
+		return "o"
+	}
+	return strings.ToLower(status[:1])
+}
@@ -0,0 +1,190 @@
+package cerb
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// snippetRadius is the number of characters of context kept on either side of a match in Message.Snippet. Without a match the snippet is the first snippetRadius*2 characters.
+const snippetRadius = 80
+
+// MessageDirection filters messages by who sent them
+type MessageDirection int
+
+// Message directions for MessageQuery
+const (
+	MessageDirectionAny      MessageDirection = iota
+	MessageDirectionIncoming                  // Sent by a customer
+	MessageDirectionOutgoing                  // Sent by a worker
+)
+
+// Message represents a single message within a ticket. @see https://cerb.ai/docs/records/types/message/
+type Message struct {
+	ID            int    `json:"id"`
+	TicketID      int    `json:"ticket_id"`
+	TicketMask    string `json:"ticket_mask"`    // Only set when `ticket_` is expanded
+	TicketSubject string `json:"ticket_subject"` // Only set when `ticket_` is expanded
+	SenderID      int    `json:"sender_id"`
+	SenderEmail   string `json:"sender_email"` // Only set when `sender_` is expanded
+	WorkerID      int    `json:"worker_id"`
+	IsOutgoing    int    `json:"is_outgoing"`
+	Created       int    `json:"created"`
+	Content       string `json:"content"` // Only set when `content` is expanded
+	URL           string `json:"record_url"`
+
+	Snippet string `json:"-"` // Text surrounding the first match of MessageQuery.Text
+}
+
+// SearchMessagesResponse is the response from the records/message/search.json endpoint
+type SearchMessagesResponse struct {
+	Status  string    `json:"__status"`
+	Count   int       `json:"count"`
+	Limit   int       `json:"limit"`
+	Page    int       `json:"page"`
+	Results []Message `json:"results"`
+	Total   int       `json:"total"`
+	Version string    `json:"__version"`
+}
+
+// MessageQuery filters messages. Empty fields are ignored.
+type MessageQuery struct {
+	Text        string // Full-text search of the message content
+	SenderEmail string
+	TicketID    int
+	Since       time.Time // Sent at or after
+	Until       time.Time // Sent at or before
+	Direction   MessageDirection
+}
+
+// query converts q into Cerb's search query syntax
+func (q MessageQuery) query() string {
+	var terms []string
+
+	if q.Text != "" {
+		terms = append(terms, `content:"`+strings.Replace(q.Text, `"`, `\"`, -1)+`"`)
+	}
+	if q.SenderEmail != "" {
+		terms = append(terms, `sender:(email:"`+q.SenderEmail+`")`)
+	}
+	if q.TicketID != 0 {
+		terms = append(terms, "ticket.id:"+strconv.Itoa(q.TicketID))
+	}
+	if !q.Since.IsZero() || !q.Until.IsZero() {
+		from, to := "big bang", "now"
+		if !q.Since.IsZero() {
+			from = q.Since.UTC().Format("2006-01-02 15:04:05 UTC")
+		}
+		if !q.Until.IsZero() {
+			to = q.Until.UTC().Format("2006-01-02 15:04:05 UTC")
+		}
+		terms = append(terms, `created:"`+from+` to `+to+`"`)
+	}
+	switch q.Direction {
+	case MessageDirectionIncoming:
+		terms = append(terms, "isOutgoing:n")
+	case MessageDirectionOutgoing:
+		terms = append(terms, "isOutgoing:y")
+	}
+
+	return strings.Join(append(terms, "sort:created"), " ")
+}
+
+// SearchMessages finds messages matching the query, oldest first. Like ListOpenTickets the results are paginated: pass the page you want and the number of messages remaining on subsequent pages is returned along with it. Each message has its ticket mask and, when q.Text is set, a snippet of the text around the match.
+func (c Cerberus) SearchMessages(q MessageQuery, page int, opts ...SearchOptions) (*[]Message, int, error) {
+	limit := 100 // Maximum of 250 enforced by server
+	params := url.Values{}
+	params.Set("q", q.query())
+	params.Set("limit", strconv.Itoa(limit))
+	params.Set("expand", "content,sender_,ticket_")
+	applySearchOptions(params, opts)
+	params.Set("page", strconv.Itoa(page)) // The page argument wins over SearchOptions.Page
+	limit = searchLimit(params)
+
+	var r SearchMessagesResponse
+	err := c.performRequest(http.MethodGet, "records/message/search.json", params, nil, &r)
+
+	if err != nil {
+		return nil, 0, fmt.Errorf("Failed to search messages: %v", err)
+	}
+
+	for i := range r.Results {
+		r.Results[i].Snippet = messageSnippet(r.Results[i].Content, q.Text)
+	}
+
+	remaining := r.Total - ((page + 1) * limit) // Page and Limit in response are incorrect
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return &r.Results, remaining, nil
+}
+
+// messageSnippet returns the text surrounding the first word of text found in content, or the start of content when nothing matches
+func messageSnippet(content string, text string) string {
+	// Work in runes, lowercasing each one on its own, so positions found in the lowercased copy are the same in the original. Lowercasing a whole string can change its length in bytes.
+	runes := []rune(strings.Join(strings.Fields(content), " "))
+	if len(runes) == 0 {
+		return ""
+	}
+
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	start, end := -1, snippetRadius // end of the match; without one the snippet is twice the radius from the start
+	for _, word := range strings.Fields(text) {
+		w := []rune(word)
+		for i := range w {
+			w[i] = unicode.ToLower(w[i])
+		}
+
+		if i := indexRunes(lower, w); i >= 0 && (start < 0 || i < start) {
+			start, end = i, i+len(w)
+		}
+	}
+	if start < 0 {
+		start = 0
+	}
+
+	from := start - snippetRadius
+	if from < 0 {
+		from = 0
+	}
+	to := end + snippetRadius
+	if to > len(runes) {
+		to = len(runes)
+	}
+
+	snippet := string(runes[from:to])
+	if from > 0 {
+		snippet = "…" + snippet
+	}
+	if to < len(runes) {
+		snippet += "…"
+	}
+
+	return snippet
+}
+
+// indexRunes returns the index of the first instance of sub in s, or -1 if sub isn't present
+func indexRunes(s []rune, sub []rune) int {
+	for i := 0; i+len(sub) <= len(s); i++ {
+		match := true
+		for j := range sub {
+			if s[i+j] != sub[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,55 @@
+package cerb
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMessageSnippet(t *testing.T) {
+	long := strings.Repeat("a ", 100)
+
+	tests := []struct {
+		name    string
+		content string
+		text    string
+		want    string
+	}{
+		{"empty content", "", "foo", ""},
+		{"no match starts at the beginning", "hello world", "foo", "hello world"},
+		{"collapses whitespace", "hello\n\n  world", "", "hello world"},
+		{"case insensitive", "Hello World", "WORLD", "Hello World"},
+		{"earliest word wins", "one two three", "three two", "one two three"},
+		{"lowercasing grows the bytes", "ȺȺȺȺȺȺ foo", "foo", "ȺȺȺȺȺȺ foo"},
+		{"lowercasing shrinks the bytes", "İİİİ foo", "foo", "İİİİ foo"},
+		{"non-ASCII match", "Grüße aus München", "MÜNCHEN", "Grüße aus München"},
+		{"trims long content", long + "needle " + long, "needle",
+			"…" + strings.Repeat("a ", 40) + "needle" + strings.Repeat(" a", 40) + "…"},
+		{"trims long content without a match", long, "needle", strings.Repeat("a ", 80) + "…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := messageSnippet(tt.content, tt.text)
+			if got != tt.want {
+				t.Errorf("messageSnippet(%q, %q) = %q, want %q", tt.content, tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessageSnippetKeepsWholeRunes(t *testing.T) {
+	content := strings.Repeat("é", 500) + " needle " + strings.Repeat("ж", 500)
+
+	got := messageSnippet(content, "needle")
+	if !strings.Contains(got, "needle") {
+		t.Errorf("snippet %q is missing the match", got)
+	}
+	if want := "…" + strings.Repeat("é", snippetRadius-1) + " needle " + strings.Repeat("ж", snippetRadius-1) + "…"; got != want {
+		t.Errorf("snippet = %q, want %d runes either side of the match", got, snippetRadius)
+	}
+	for _, r := range got {
+		if r == '�' {
+			t.Fatalf("snippet %q contains a broken rune", got)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package cerb
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Org represents an organization in Cerb. @see https://cerb.ai/docs/records/types/org/
+type Org struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Street   string `json:"street"`
+	City     string `json:"city"`
+	Province string `json:"province"`
+	Postal   string `json:"postal"`
+	Country  string `json:"country"`
+	Phone    string `json:"phone"`
+	Website  string `json:"website"`
+	EmailID  int    `json:"email_id"`
+	URL      string `json:"record_url"`
+	Created  int    `json:"created"`
+	Updated  int    `json:"updated"`
+}
+
+// SearchOrgsResponse is the response from the records/org/search.json endpoint
+type SearchOrgsResponse struct {
+	Status  string `json:"__status"`
+	Count   int    `json:"count"`
+	Limit   int    `json:"limit"`
+	Page    int    `json:"page"`
+	Results []Org  `json:"results"`
+	Total   int    `json:"total"`
+	Version string `json:"__version"`
+}
+
+// orgForm converts the non-zero fields of o into the form used by the create and update endpoints
+func orgForm(o Org) url.Values {
+	form := url.Values{}
+	fields := map[string]string{
+		"name":     o.Name,
+		"street":   o.Street,
+		"city":     o.City,
+		"province": o.Province,
+		"postal":   o.Postal,
+		"country":  o.Country,
+		"phone":    o.Phone,
+		"website":  o.Website,
+	}
+
+	for k, v := range fields {
+		if v != "" {
+			form.Set("fields["+k+"]", v)
+		}
+	}
+
+	if o.EmailID != 0 {
+		form.Set("fields[email_id]", strconv.Itoa(o.EmailID))
+	}
+
+	return form
+}
+
+// CreateOrg creates a new organization. Name is required.
+func (c Cerberus) CreateOrg(o Org) (*Org, error) {
+	var created Org
+	err := c.performRequest(http.MethodPost, "records/org/create.json", nil, orgForm(o), &created)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create org %s: %v", o.Name, err)
+	}
+
+	return &created, nil
+}
+
+// GetOrg loads the organization with the given ID
+func (c Cerberus) GetOrg(orgID int) (*Org, error) {
+	var o Org
+	err := c.performRequest(http.MethodGet, "records/org/"+strconv.Itoa(orgID)+".json", nil, nil, &o)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get org %d: %v", orgID, err)
+	}
+
+	return &o, nil
+}
+
+// UpdateOrg updates the organization with the non-zero fields of o. o.ID must be set.
+func (c Cerberus) UpdateOrg(o Org) (*Org, error) {
+	var updated Org
+	err := c.performRequest(http.MethodPut, "records/org/"+strconv.Itoa(o.ID)+".json", nil, orgForm(o), &updated)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update org %d: %v", o.ID, err)
+	}
+
+	return &updated, nil
+}
+
+// DeleteOrg permanently deletes the organization with the given ID
+func (c Cerberus) DeleteOrg(orgID int) error {
+	return c.deleteRecord("org", orgID)
+}
+
+// SearchOrgs finds organizations matching the given Cerb search query, e.g. `name:"AgileBits*"`
+func (c Cerberus) SearchOrgs(query string, opts ...SearchOptions) (*[]Org, error) {
+	limit := 250 // If you need pagination imitate ListOpenTickets
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(limit))
+	applySearchOptions(params, opts)
+
+	var r SearchOrgsResponse
+	err := c.performRequest(http.MethodGet, "records/org/search.json", params, nil, &r)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to search orgs: %v", err)
+	}
+
+	return &r.Results, nil
+}
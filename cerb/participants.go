@@ -0,0 +1,152 @@
+package cerb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Participants (requesters) are the addresses that receive replies to a ticket. They're managed through the ticket's `participants` field: a comma separated list of addresses to add, with a `-` prefix on the addresses to remove.
+
+// ParticipantsResult reports what happened to each address passed to AddTicketParticipants or RemoveTicketParticipants. Addresses are normalized to their lowercase email.
+type ParticipantsResult struct {
+	Changed   []string // Added or removed as requested
+	Unchanged []string // Already a participant (when adding) or not one (when removing)
+	Invalid   []string // Not a valid email address, so never sent to Cerb
+	Rejected  []string // Sent to Cerb but not applied, e.g. banned addresses or the group's own reply-to address
+}
+
+// ListTicketParticipants lists the addresses participating in the ticket, in address ID order
+func (c Cerberus) ListTicketParticipants(ticketID int) (*[]Address, error) {
+	params := url.Values{}
+	params.Set("expand", "requesters")
+
+	var r struct {
+		Requesters json.RawMessage `json:"requesters"`
+	}
+	err := c.performRequest(http.MethodGet, "records/ticket/"+strconv.Itoa(ticketID)+".json", params, nil, &r)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list participants of ticket %d: %v", ticketID, err)
+	}
+
+	addresses := []Address{}
+	err = decodeExpandedRecords(r.Requesters, &addresses)
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding participants of ticket %d: %v", ticketID, err)
+	}
+
+	return &addresses, nil
+}
+
+// AddTicketParticipants adds the email addresses as participants of the ticket. Invalid and duplicate addresses are skipped.
+func (c Cerberus) AddTicketParticipants(ticketID int, emails ...string) (*ParticipantsResult, error) {
+	return c.updateTicketParticipants(ticketID, emails, false)
+}
+
+// RemoveTicketParticipants removes the email addresses from the participants of the ticket
+func (c Cerberus) RemoveTicketParticipants(ticketID int, emails ...string) (*ParticipantsResult, error) {
+	return c.updateTicketParticipants(ticketID, emails, true)
+}
+
+func (c Cerberus) updateTicketParticipants(ticketID int, emails []string, remove bool) (*ParticipantsResult, error) {
+	result := ParticipantsResult{}
+	valid, invalid := normalizeEmails(emails)
+	result.Invalid = invalid
+
+	current, err := c.participantSet(ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []string
+	for _, email := range valid {
+		if current[email] != remove {
+			result.Unchanged = append(result.Unchanged, email)
+			continue
+		}
+
+		pending = append(pending, email)
+	}
+
+	if len(pending) == 0 {
+		return &result, nil
+	}
+
+	prefix := ""
+	if remove {
+		prefix = "-"
+	}
+
+	values := make([]string, len(pending))
+	for i, email := range pending {
+		values[i] = prefix + email
+	}
+
+	form := url.Values{}
+	form.Set("fields[participants]", strings.Join(values, ","))
+
+	var r recordStatusResponse
+	err = c.performRequest(http.MethodPut, "records/ticket/"+strconv.Itoa(ticketID)+".json", nil, form, &r)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update participants of ticket %d: %v", ticketID, err)
+	}
+
+	// Cerb silently ignores addresses it won't accept so compare against the new participant list to find them
+	updated, err := c.participantSet(ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, email := range pending {
+		if updated[email] != remove {
+			result.Changed = append(result.Changed, email)
+		} else {
+			result.Rejected = append(result.Rejected, email)
+		}
+	}
+
+	return &result, nil
+}
+
+func (c Cerberus) participantSet(ticketID int) (map[string]bool, error) {
+	participants, err := c.ListTicketParticipants(ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]bool, len(*participants))
+	for _, a := range *participants {
+		set[strings.ToLower(a.Email)] = true
+	}
+
+	return set, nil
+}
+
+// normalizeEmails parses each address (accepting forms like `Jane <jane@example.com>`) and returns the unique, lowercased emails along with the inputs that failed to parse
+func normalizeEmails(emails []string) (valid []string, invalid []string) {
+	seen := map[string]bool{}
+
+	for _, e := range emails {
+		a, err := mail.ParseAddress(strings.TrimSpace(e))
+		if err != nil {
+			invalid = append(invalid, e)
+			continue
+		}
+
+		email := strings.ToLower(a.Address)
+		if seen[email] {
+			continue
+		}
+
+		seen[email] = true
+		valid = append(valid, email)
+	}
+
+	return valid, invalid
+}
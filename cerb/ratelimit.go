@@ -0,0 +1,43 @@
+package cerb
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter spaces requests evenly so a client never exceeds its configured requests per second. It's shared by every copy of a Cerberus so concurrent callers share the budget.
+type rateLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// WithRateLimit returns a copy of the client that makes at most requestsPerSecond requests per second, across all goroutines using it. Use 0 to remove the limit.
+func (c Cerberus) WithRateLimit(requestsPerSecond float64) Cerberus {
+	if requestsPerSecond <= 0 {
+		c.limiter = nil
+		return c
+	}
+
+	c.limiter = &rateLimiter{interval: time.Duration(float64(time.Second) / requestsPerSecond)}
+	return c
+}
+
+// wait blocks until the caller may make its next request
+func (l *rateLimiter) wait() {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	time.Sleep(time.Until(slot))
+}
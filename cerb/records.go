@@ -0,0 +1,58 @@
+package cerb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// recordStatusResponse is returned by endpoints such as DELETE records/{context}/{id}.json that have no record to return
+type recordStatusResponse struct {
+	Status  string `json:"__status"`
+	Version string `json:"__version"`
+}
+
+// deleteRecord permanently deletes the record of the given context (e.g. "contact", "org")
+func (c Cerberus) deleteRecord(context string, id int) error {
+	var r recordStatusResponse
+	err := c.performRequest(http.MethodDelete, "records/"+context+"/"+strconv.Itoa(id)+".json", nil, nil, &r)
+
+	if err != nil {
+		return fmt.Errorf("Failed to delete %s %d: %v", context, id, err)
+	}
+
+	return nil
+}
+
+// decodeExpandedRecords decodes an expanded list of records such as a ticket's requesters or a record's watchers into records, a pointer to a slice. Cerb returns these keyed by record ID, or as an empty list when there are none, so keyed records are put in ID order.
+func decodeExpandedRecords(raw json.RawMessage, records interface{}) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+
+	var byID map[string]json.RawMessage
+	if json.Unmarshal(raw, &byID) == nil {
+		ids := make([]int, 0, len(byID))
+		values := map[int]json.RawMessage{}
+		for key, value := range byID {
+			id, err := strconv.Atoi(key)
+			if err != nil {
+				return fmt.Errorf("Unexpected record ID %q: %v", key, err)
+			}
+			ids = append(ids, id)
+			values[id] = value
+		}
+		sort.Ints(ids)
+
+		ordered := make([][]byte, len(ids))
+		for i, id := range ids {
+			ordered[i] = values[id]
+		}
+		raw = append(append([]byte("["), bytes.Join(ordered, []byte(","))...), ']')
+	}
+
+	return json.Unmarshal(raw, records)
+}
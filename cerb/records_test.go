@@ -0,0 +1,45 @@
+package cerb
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeExpandedRecords(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want []int
+	}{
+		{"missing", ``, []int{}},
+		{"null", `null`, []int{}},
+		{"empty list", `[]`, []int{}},
+		{"list keeps its order", `[{"id":3},{"id":1}]`, []int{3, 1}},
+		{"keyed by ID in ID order", `{"12":{"id":12},"3":{"id":3},"7":{"id":7}}`, []int{3, 7, 12}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records := []struct {
+				ID int `json:"id"`
+			}{}
+			err := decodeExpandedRecords(json.RawMessage(tt.raw), &records)
+			if err != nil {
+				t.Fatalf("decodeExpandedRecords() error = %v", err)
+			}
+
+			got := []int{}
+			for _, r := range records {
+				got = append(got, r.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeExpandedRecords() IDs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if err := decodeExpandedRecords(json.RawMessage(`{"x":{"id":1}}`), &[]Worker{}); err == nil {
+		t.Error("decodeExpandedRecords() with a non-numeric key should fail")
+	}
+}
@@ -0,0 +1,323 @@
+package cerb
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Queue reports are built from ticket counts rather than the tickets themselves: each count is a search with a limit of 1 that only reads the total, and the per-bucket counts come from Cerb's subtotals of those searches. A report takes one request per status, one per age range and a capped scan for the oldest unanswered ticket no matter how many buckets there are.
+
+// defaultUnansweredScan is how many open tickets QueueReport looks through for the oldest unanswered one when QueueReportOptions.MaxUnansweredScan isn't set
+const defaultUnansweredScan = 500
+
+// DefaultAgeBoundaries splits open tickets into under a day, 1-3 days, 3-7 days, 1-4 weeks and older
+var DefaultAgeBoundaries = []time.Duration{24 * time.Hour, 72 * time.Hour, 7 * 24 * time.Hour, 28 * 24 * time.Hour}
+
+// QueueReportOptions controls what QueueReport counts
+type QueueReportOptions struct {
+	// Query restricts the tickets counted, e.g. `group.id:3`. Empty counts every ticket.
+	Query string
+	// AgeBoundaries are the upper bounds of each age range, shortest first. Defaults to DefaultAgeBoundaries.
+	AgeBoundaries []time.Duration
+	// Now is the time ages are measured from. Defaults to the current time.
+	Now time.Time
+	// MaxUnansweredScan is how many of the oldest open tickets are searched for one without a reply. Defaults to 500.
+	MaxUnansweredScan int
+}
+
+// BucketQueue counts the open and waiting tickets in a bucket
+type BucketQueue struct {
+	GroupID    int
+	GroupName  string
+	BucketID   int
+	BucketName string
+	Open       int
+	Waiting    int
+}
+
+// AgeRange counts the open tickets created at least Min but less than Max ago, so a ticket on a boundary is only counted in the newer range. Max is 0 for the last, unbounded, range.
+type AgeRange struct {
+	Min   time.Duration
+	Max   time.Duration
+	Count int
+}
+
+// Label describes the range, e.g. "1d-3d" or "28d+"
+func (a AgeRange) Label() string {
+	if a.Max == 0 {
+		return formatAge(a.Min) + "+"
+	}
+	return formatAge(a.Min) + "-" + formatAge(a.Max)
+}
+
+// QueueReport is a snapshot of the ticket backlog
+type QueueReport struct {
+	Generated time.Time
+	Open      int
+	Waiting   int
+	Buckets   []BucketQueue
+	Ages      []AgeRange
+	// OldestUnanswered is the oldest open ticket no worker has replied to. It's nil when none of the oldest MaxUnansweredScan open tickets is unanswered.
+	OldestUnanswered *CerberusTicket
+}
+
+// ticketSubtotal is one row of the subtotals Cerb returns with a search
+type ticketSubtotal struct {
+	Label string      `json:"label"`
+	Value json.Number `json:"value"`
+	Hits  int         `json:"hits"`
+}
+
+// ticketSubtotalResults is the response from records/ticket/search.json when only counts are wanted
+type ticketSubtotalResults struct {
+	Total     int                         `json:"total"`
+	Subtotals map[string][]ticketSubtotal `json:"subtotals"`
+}
+
+// CountTickets returns the number of tickets matching the Cerb search query without loading them
+func (c Cerberus) CountTickets(query string) (int, error) {
+	r, err := c.countTickets(query, "")
+	if err != nil {
+		return 0, err
+	}
+
+	return r.Total, nil
+}
+
+// countTickets counts the tickets matching query and, when subtotal is set, subtotals them by that field (e.g. "bucket")
+func (c Cerberus) countTickets(query string, subtotal string) (*ticketSubtotalResults, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", "1")
+	if subtotal != "" {
+		params.Set("subtotals", subtotal)
+	}
+
+	var r ticketSubtotalResults
+	err := c.performRequest(http.MethodGet, "records/ticket/search.json", params, nil, &r)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to count tickets matching %q: %v", query, err)
+	}
+
+	return &r, nil
+}
+
+// countByBucket counts the tickets matching query in total and by bucket ID
+func (c Cerberus) countByBucket(query string) (int, map[int]int, error) {
+	r, err := c.countTickets(query, "bucket")
+	if err != nil {
+		return 0, nil, err
+	}
+
+	counts := map[int]int{}
+	for _, st := range r.Subtotals["bucket"] {
+		id, err := strconv.Atoi(st.Value.String())
+		if err != nil {
+			return 0, nil, fmt.Errorf("Unexpected bucket %q in ticket subtotals: %v", st.Value, err)
+		}
+		counts[id] += st.Hits
+	}
+
+	return r.Total, counts, nil
+}
+
+// QueueReport counts the open and waiting tickets overall and in every bucket, splits the open tickets by age and finds the oldest unanswered ticket. When opts.Query is set only the buckets holding matching tickets are included.
+func (c Cerberus) QueueReport(opts QueueReportOptions) (*QueueReport, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	boundaries := opts.AgeBoundaries
+	if len(boundaries) == 0 {
+		boundaries = DefaultAgeBoundaries
+	}
+	maxScan := opts.MaxUnansweredScan
+	if maxScan <= 0 {
+		maxScan = defaultUnansweredScan
+	}
+
+	query := func(terms ...string) string {
+		return strings.TrimSpace(opts.Query + " " + strings.Join(terms, " "))
+	}
+
+	report := QueueReport{Generated: now}
+
+	open, openByBucket, err := c.countByBucket(query("status:o"))
+	if err != nil {
+		return nil, err
+	}
+	waiting, waitingByBucket, err := c.countByBucket(query("status:w"))
+	if err != nil {
+		return nil, err
+	}
+	report.Open = open
+	report.Waiting = waiting
+
+	groups, err := c.CachedGroupsAndBuckets()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list buckets for queue report: %v", err)
+	}
+
+	for _, g := range groups {
+		for _, b := range g.Buckets {
+			bq := BucketQueue{
+				GroupID:    g.ID,
+				GroupName:  g.Name,
+				BucketID:   b.ID,
+				BucketName: b.Name,
+				Open:       openByBucket[b.ID],
+				Waiting:    waitingByBucket[b.ID],
+			}
+
+			if opts.Query != "" && bq.Open == 0 && bq.Waiting == 0 {
+				continue // Outside the query, or at least empty within it
+			}
+
+			report.Buckets = append(report.Buckets, bq)
+		}
+	}
+
+	min := time.Duration(0)
+	for i := 0; i <= len(boundaries); i++ {
+		a := AgeRange{Min: min}
+		from := "big bang"
+		if i < len(boundaries) {
+			a.Max = boundaries[i]
+			from = now.Add(-a.Max + time.Second).UTC().Format("2006-01-02 15:04:05 UTC") // Both ends of a created range are inclusive and timestamps are in seconds, so this excludes Max itself
+		}
+		to := now.Add(-a.Min).UTC().Format("2006-01-02 15:04:05 UTC")
+
+		a.Count, err = c.CountTickets(query("status:o", `created:"`+from+` to `+to+`"`))
+		if err != nil {
+			return nil, err
+		}
+
+		report.Ages = append(report.Ages, a)
+		min = a.Max
+	}
+
+	report.OldestUnanswered, err = c.oldestUnansweredTicket(opts.Query, maxScan)
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// oldestUnansweredTicket looks through at most maxScan open tickets, oldest first, for one without a worker reply. Only the fields needed are requested to keep the pages small.
+func (c Cerberus) oldestUnansweredTicket(query string, maxScan int) (*CerberusTicket, error) {
+	limit := 250 // Maximum of 250 enforced by server
+	if maxScan < limit {
+		limit = maxScan
+	}
+	params := url.Values{}
+	params.Set("q", strings.TrimSpace(query+" status:o sort:created"))
+	params.Set("limit", strconv.Itoa(limit))
+	params.Set("fields", "id,mask,subject,status,group_id,bucket_id,created,updated,elapsed_response_first")
+
+	for page := 0; page*limit < maxScan; page++ {
+		params.Set("page", strconv.Itoa(page))
+
+		var r CerberusTicketSearchResults
+		err := c.performRequest(http.MethodGet, "records/ticket/search.json", params, nil, &r)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to search for unanswered tickets: %v", err)
+		}
+
+		for i := range r.Results {
+			if r.Results[i].ElapsedResponseFirst == 0 {
+				return &r.Results[i], nil
+			}
+		}
+
+		if len(r.Results) == 0 || (page+1)*limit >= r.Total {
+			break
+		}
+	}
+
+	return nil, nil
+}
+
+// WriteText writes the report as aligned plain text, suitable for email or chat
+func (r QueueReport) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Ticket queue at %s\n\n", r.Generated.Format("2006-01-02 15:04 MST"))
+	fmt.Fprintf(tw, "Open\t%d\n", r.Open)
+	fmt.Fprintf(tw, "Waiting\t%d\n", r.Waiting)
+
+	fmt.Fprintf(tw, "\nGroup\tBucket\tOpen\tWaiting\n")
+	for _, b := range r.Buckets {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\n", b.GroupName, b.BucketName, b.Open, b.Waiting)
+	}
+
+	fmt.Fprintf(tw, "\nAge\tOpen\n")
+	for _, a := range r.Ages {
+		fmt.Fprintf(tw, "%s\t%d\n", a.Label(), a.Count)
+	}
+
+	if t := r.OldestUnanswered; t != nil {
+		created := time.Unix(int64(t.Created), 0)
+		fmt.Fprintf(tw, "\nOldest unanswered: %s %q (%s old)\n", t.Mask, t.Subject, formatAge(r.Generated.Sub(created)))
+	} else {
+		fmt.Fprintf(tw, "\nOldest unanswered: none\n")
+	}
+
+	return tw.Flush()
+}
+
+// WriteCSV writes the report as CSV with a header row followed by rows of generated time, metric, group, bucket and value. Use WriteCSVRows to append later reports to the same file.
+func (r QueueReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"generated", "metric", "group", "bucket", "value"})
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+
+	return r.WriteCSVRows(w)
+}
+
+// WriteCSVRows writes the report's CSV rows without the header row, so reports from different days can be appended to one spreadsheet
+func (r QueueReport) WriteCSVRows(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	date := r.Generated.Format(time.RFC3339)
+
+	rows := [][]string{
+		{date, "open", "", "", strconv.Itoa(r.Open)},
+		{date, "waiting", "", "", strconv.Itoa(r.Waiting)},
+	}
+	for _, b := range r.Buckets {
+		rows = append(rows,
+			[]string{date, "open", b.GroupName, b.BucketName, strconv.Itoa(b.Open)},
+			[]string{date, "waiting", b.GroupName, b.BucketName, strconv.Itoa(b.Waiting)},
+		)
+	}
+	for _, a := range r.Ages {
+		rows = append(rows, []string{date, "age " + a.Label(), "", "", strconv.Itoa(a.Count)})
+	}
+	if t := r.OldestUnanswered; t != nil {
+		rows = append(rows, []string{date, "oldest unanswered", "", "", t.Mask})
+	}
+
+	cw.WriteAll(rows)
+	return cw.Error()
+}
+
+// formatAge rounds d to whole days, or hours when under a day
+func formatAge(d time.Duration) string {
+	if d < 24*time.Hour {
+		return strconv.Itoa(int(d.Hours())) + "h"
+	}
+	return strconv.Itoa(int(d.Hours()/24)) + "d"
+}
@@ -0,0 +1,40 @@
+package cerb
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestQueueReportCSV(t *testing.T) {
+	r := QueueReport{
+		Generated: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+		Open:      3,
+		Waiting:   1,
+		Buckets:   []BucketQueue{{GroupName: "Support", BucketName: "Inbox", Open: 3, Waiting: 1}},
+		Ages:      []AgeRange{{Min: 0, Max: 24 * time.Hour, Count: 2}, {Min: 24 * time.Hour, Count: 1}},
+	}
+
+	rows := "2026-10-18T09:00:00Z,open,,,3\n" +
+		"2026-10-18T09:00:00Z,waiting,,,1\n" +
+		"2026-10-18T09:00:00Z,open,Support,Inbox,3\n" +
+		"2026-10-18T09:00:00Z,waiting,Support,Inbox,1\n" +
+		"2026-10-18T09:00:00Z,age 0h-1d,,,2\n" +
+		"2026-10-18T09:00:00Z,age 1d+,,,1\n"
+
+	var b bytes.Buffer
+	if err := r.WriteCSV(&b); err != nil {
+		t.Fatal(err)
+	}
+	if want := "generated,metric,group,bucket,value\n" + rows; b.String() != want {
+		t.Errorf("WriteCSV() =\n%s\nwant\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	if err := r.WriteCSVRows(&b); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != rows {
+		t.Errorf("WriteCSVRows() =\n%s\nwant\n%s", b.String(), rows)
+	}
+}
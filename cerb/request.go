@@ -19,6 +19,8 @@ import (
 // @see https://cerb.ai/docs/api/authentication/ for details.
 
 func (c Cerberus) performRequest(method string, endpoint string, params url.Values, form url.Values, target interface{}) error {
+	c.limiter.wait()
+
 	location, _ := time.LoadLocation("GMT")
 	t := time.Now().In(location)
 	date := t.Format(time.RFC1123)
@@ -0,0 +1,132 @@
+package cerb
+
+import (
+	"fmt"
+	"time"
+)
+
+// Response times are worked out from a ticket's message timeline. The ticket is in the workers' court from a customer message until the next worker reply and in the customer's court (waiting) from a worker reply until the customer writes back, which is how Cerb moves tickets between open and waiting.
+
+// ResponseTimeOptions controls how response times are measured
+type ResponseTimeOptions struct {
+	// Hours counts only working time. Nil counts every hour of every day.
+	Hours *BusinessHours
+	// IncludeWaiting counts the time spent waiting on the customer in the resolution time. By default it's excluded.
+	IncludeWaiting bool
+}
+
+// ResponseTimes measures how quickly a ticket was answered and resolved
+type ResponseTimes struct {
+	TicketID int
+	// FirstResponse is the time from the first customer message to the first worker reply. Only set when Responded.
+	FirstResponse time.Duration
+	Responded     bool
+	// Resolution is the time from the first customer message until the ticket was closed. Only set when Resolved.
+	Resolution time.Duration
+	Resolved   bool
+	// BackAndForths counts the customer messages (or runs of them) that a worker replied to
+	BackAndForths int
+	Messages      int
+}
+
+// TicketResponseTimes loads the ticket and its messages and measures its response times
+func (c Cerberus) TicketResponseTimes(ticketID int, opts ResponseTimeOptions) (*ResponseTimes, error) {
+	t, err := c.GetTicket(ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs, err := c.ticketTimeline(ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	rt := MeasureResponseTimes(*t, msgs, opts)
+	return &rt, nil
+}
+
+// ResponseTimesForTickets measures each of the tickets. Every ticket is attempted; failures are returned as TicketErrors along with the tickets that succeeded.
+func (c Cerberus) ResponseTimesForTickets(ticketIDs []int, opts ResponseTimeOptions) ([]ResponseTimes, error) {
+	times := []ResponseTimes{}
+
+	err := eachTicket(ticketIDs, func(id int) error {
+		rt, err := c.TicketResponseTimes(id, opts)
+		if err != nil {
+			return err
+		}
+		times = append(times, *rt)
+		return nil
+	})
+
+	return times, err
+}
+
+// ticketTimeline loads every message of the ticket, oldest first
+func (c Cerberus) ticketTimeline(ticketID int) ([]Message, error) {
+	search := SearchOptions{Limit: 250, Expand: []string{}} // Only the direction and time of each message are needed
+
+	msgs := []Message{}
+	for page := 0; ; page++ {
+		results, remaining, err := c.SearchMessages(MessageQuery{TicketID: ticketID}, page, search)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load messages of ticket %d: %v", ticketID, err)
+		}
+
+		msgs = append(msgs, *results...)
+
+		if len(*results) == 0 || remaining == 0 {
+			return msgs, nil
+		}
+	}
+}
+
+// MeasureResponseTimes measures the response times of t from its messages, which must be sorted oldest first. Worker messages sent before the customer's first message (e.g. a ticket opened by a worker) don't count as responses, and nor do outgoing messages without a worker such as auto-replies.
+func MeasureResponseTimes(t CerberusTicket, msgs []Message, opts ResponseTimeOptions) ResponseTimes {
+	rt := ResponseTimes{TicketID: t.ID, Messages: len(msgs)}
+
+	var first, turnStarted time.Time // turnStarted is set while the ticket is in the workers' court
+	var working time.Duration
+
+	for _, m := range msgs {
+		sent := time.Unix(int64(m.Created), 0)
+
+		if m.IsOutgoing == 0 {
+			if first.IsZero() {
+				first = sent
+			}
+			if turnStarted.IsZero() {
+				turnStarted = sent
+			}
+			continue
+		}
+
+		if m.WorkerID == 0 || turnStarted.IsZero() {
+			continue
+		}
+
+		if !rt.Responded {
+			rt.FirstResponse = opts.Hours.Elapsed(first, sent)
+			rt.Responded = true
+		}
+		rt.BackAndForths++
+		working += opts.Hours.Elapsed(turnStarted, sent)
+		turnStarted = time.Time{}
+	}
+
+	if ticketStatusCode(t.Status) != "c" || t.ClosedAt == 0 || first.IsZero() {
+		return rt
+	}
+
+	closed := time.Unix(int64(t.ClosedAt), 0)
+	if !turnStarted.IsZero() {
+		working += opts.Hours.Elapsed(turnStarted, closed) // Closed without a reply to the last customer message
+	}
+
+	rt.Resolved = true
+	rt.Resolution = working
+	if opts.IncludeWaiting {
+		rt.Resolution = opts.Hours.Elapsed(first, closed)
+	}
+
+	return rt
+}
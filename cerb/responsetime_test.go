@@ -0,0 +1,126 @@
+package cerb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMeasureResponseTimes(t *testing.T) {
+	friday := time.Date(2026, 10, 16, 16, 0, 0, 0, time.UTC)
+	at := func(hours float64) int {
+		return int(friday.Add(time.Duration(hours * float64(time.Hour))).Unix())
+	}
+	in := func(hours float64) Message { return Message{Created: at(hours)} }
+	out := func(hours float64) Message { return Message{Created: at(hours), IsOutgoing: 1, WorkerID: 7} }
+	auto := func(hours float64) Message { return Message{Created: at(hours), IsOutgoing: 1} }
+
+	weekdays := NewBusinessHours(time.UTC, 9*time.Hour, 17*time.Hour, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+	open := CerberusTicket{ID: 1, Status: "open"}
+	closedAt := func(hours float64) CerberusTicket {
+		return CerberusTicket{ID: 1, Status: "closed", ClosedAt: at(hours)}
+	}
+
+	tests := []struct {
+		name   string
+		ticket CerberusTicket
+		msgs   []Message
+		opts   ResponseTimeOptions
+		want   ResponseTimes
+	}{
+		{
+			name:   "no messages",
+			ticket: open,
+			want:   ResponseTimes{TicketID: 1},
+		},
+		{
+			name:   "unanswered",
+			ticket: open,
+			msgs:   []Message{in(0), in(1)},
+			want:   ResponseTimes{TicketID: 1, Messages: 2},
+		},
+		{
+			name:   "answered and closed",
+			ticket: closedAt(3),
+			msgs:   []Message{in(0), out(2)},
+			want:   ResponseTimes{TicketID: 1, FirstResponse: 2 * time.Hour, Responded: true, Resolution: 2 * time.Hour, Resolved: true, BackAndForths: 1, Messages: 2},
+		},
+		{
+			name:   "auto-replies aren't responses",
+			ticket: closedAt(3),
+			msgs:   []Message{in(0), auto(0), out(2)},
+			want:   ResponseTimes{TicketID: 1, FirstResponse: 2 * time.Hour, Responded: true, Resolution: 2 * time.Hour, Resolved: true, BackAndForths: 1, Messages: 3},
+		},
+		{
+			name:   "waiting on the customer is excluded",
+			ticket: closedAt(10),
+			msgs:   []Message{in(0), out(1), in(5), out(6)},
+			want:   ResponseTimes{TicketID: 1, FirstResponse: time.Hour, Responded: true, Resolution: 2 * time.Hour, Resolved: true, BackAndForths: 2, Messages: 4},
+		},
+		{
+			name:   "waiting on the customer can be included",
+			ticket: closedAt(10),
+			msgs:   []Message{in(0), out(1), in(5), out(6)},
+			opts:   ResponseTimeOptions{IncludeWaiting: true},
+			want:   ResponseTimes{TicketID: 1, FirstResponse: time.Hour, Responded: true, Resolution: 10 * time.Hour, Resolved: true, BackAndForths: 2, Messages: 4},
+		},
+		{
+			name:   "a run of customer messages is one back and forth timed from the first",
+			ticket: closedAt(4),
+			msgs:   []Message{in(0), in(1), in(2), out(3)},
+			want:   ResponseTimes{TicketID: 1, FirstResponse: 3 * time.Hour, Responded: true, Resolution: 3 * time.Hour, Resolved: true, BackAndForths: 1, Messages: 4},
+		},
+		{
+			name:   "a run of worker replies is one back and forth",
+			ticket: open,
+			msgs:   []Message{in(0), out(1), out(2), in(3), out(4)},
+			want:   ResponseTimes{TicketID: 1, FirstResponse: time.Hour, Responded: true, BackAndForths: 2, Messages: 5},
+		},
+		{
+			name:   "worker messages before the customer's are not responses",
+			ticket: open,
+			msgs:   []Message{out(0), in(1), out(3)},
+			want:   ResponseTimes{TicketID: 1, FirstResponse: 2 * time.Hour, Responded: true, BackAndForths: 1, Messages: 3},
+		},
+		{
+			name:   "closed without replying to the last customer message",
+			ticket: closedAt(6),
+			msgs:   []Message{in(0), out(1), in(4)},
+			want:   ResponseTimes{TicketID: 1, FirstResponse: time.Hour, Responded: true, Resolution: 3 * time.Hour, Resolved: true, BackAndForths: 1, Messages: 3},
+		},
+		{
+			name:   "closed without any customer message isn't resolved",
+			ticket: closedAt(2),
+			msgs:   []Message{out(0)},
+			want:   ResponseTimes{TicketID: 1, Messages: 1},
+		},
+		{
+			name:   "open tickets aren't resolved",
+			ticket: CerberusTicket{ID: 1, Status: "open", ClosedAt: at(5)},
+			msgs:   []Message{in(0), out(1)},
+			want:   ResponseTimes{TicketID: 1, FirstResponse: time.Hour, Responded: true, BackAndForths: 1, Messages: 2},
+		},
+		{
+			name:   "business hours skip the weekend",
+			ticket: closedAt(67),
+			msgs:   []Message{in(0), out(66)},
+			opts:   ResponseTimeOptions{Hours: weekdays},
+			want:   ResponseTimes{TicketID: 1, FirstResponse: 2 * time.Hour, Responded: true, Resolution: 2 * time.Hour, Resolved: true, BackAndForths: 1, Messages: 2},
+		},
+		{
+			name:   "business hours with waiting included",
+			ticket: closedAt(72),
+			msgs:   []Message{in(0), out(66), in(70), out(71)},
+			opts:   ResponseTimeOptions{Hours: weekdays, IncludeWaiting: true},
+			want:   ResponseTimes{TicketID: 1, FirstResponse: 2 * time.Hour, Responded: true, Resolution: 8 * time.Hour, Resolved: true, BackAndForths: 2, Messages: 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MeasureResponseTimes(tt.ticket, tt.msgs, tt.opts)
+			if got != tt.want {
+				t.Errorf("MeasureResponseTimes() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package cerb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Group and bucket IDs differ between Cerb instances (e.g. staging and production) so it's often easier to route tickets by name. Destinations are written as "Group/Bucket", or just "Group" to use the group's default bucket. Names are matched case-insensitively against the group/bucket tree from the client's cache.
+
+var (
+	// ErrDestinationNotFound is returned when no group/bucket matches a destination
+	ErrDestinationNotFound = errors.New("destination not found")
+
+	// ErrAmbiguousDestination is returned when more than one group/bucket matches a destination
+	ErrAmbiguousDestination = errors.New("destination is ambiguous")
+)
+
+// Route is a resolved destination for a ticket
+type Route struct {
+	GroupID    int
+	GroupName  string
+	BucketID   int
+	BucketName string
+}
+
+func (r Route) String() string {
+	return r.GroupName + "/" + r.BucketName
+}
+
+// InvalidateRoutes discards the cached group/bucket tree so the next ResolveDestination reloads it
+func (c Cerberus) InvalidateRoutes() {
+	c.InvalidateCache(CacheKeyGroups)
+}
+
+// ResolveDestination resolves a "Group/Bucket" (or "Group") destination to its IDs. The returned error wraps ErrDestinationNotFound or ErrAmbiguousDestination when the name can't be resolved to exactly one bucket.
+func (c Cerberus) ResolveDestination(destination string) (*Route, error) {
+	groups, err := c.CachedGroupsAndBuckets()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load groups and buckets for routing: %v", err)
+	}
+
+	destination = strings.TrimSpace(destination)
+	var matches []Route
+
+	for _, g := range groups {
+		// Group names may themselves contain a '/' so match on the group name as a prefix rather than splitting the destination
+		if strings.EqualFold(destination, g.Name) {
+			for _, b := range g.Buckets {
+				if b.Default == 1 {
+					matches = append(matches, Route{GroupID: g.ID, GroupName: g.Name, BucketID: b.ID, BucketName: b.Name})
+				}
+			}
+			continue
+		}
+
+		if len(destination) <= len(g.Name) || !strings.EqualFold(destination[:len(g.Name)], g.Name) || destination[len(g.Name)] != '/' {
+			continue
+		}
+
+		bucketName := strings.TrimSpace(destination[len(g.Name)+1:])
+		for _, b := range g.Buckets {
+			if strings.EqualFold(bucketName, b.Name) {
+				matches = append(matches, Route{GroupID: g.ID, GroupName: g.Name, BucketID: b.ID, BucketName: b.Name})
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: no group/bucket named %q", ErrDestinationNotFound, destination)
+	case 1:
+		return &matches[0], nil
+	}
+
+	names := make([]string, len(matches))
+	for i, m := range matches {
+		names[i] = fmt.Sprintf("%s (group %d, bucket %d)", m, m.GroupID, m.BucketID)
+	}
+
+	return nil, fmt.Errorf("%w: %q matches %s", ErrAmbiguousDestination, destination, strings.Join(names, ", "))
+}
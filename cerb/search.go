@@ -0,0 +1,88 @@
+package cerb
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Every search function accepts an optional SearchOptions to control the size and order of what Cerb returns. Each function has its own defaults (e.g. ListOpenTickets expands `initial_message_sender_`) which are kept unless overridden.
+
+// maxSearchLimit is the most results Cerb returns per page
+const maxSearchLimit = 250
+
+// SortField orders search results by a field, e.g. {Field: "updated", Descending: true}
+type SortField struct {
+	Field      string
+	Descending bool
+}
+
+// SearchOptions customizes a search. Zero values keep the search function's defaults.
+type SearchOptions struct {
+	// Query is added to the function's own query to narrow the results further, e.g. `group.id:3`
+	Query string
+	// Sort replaces the default order of the results
+	Sort []SortField
+	// Limit is the number of results per page. Larger limits are lowered to 250, the most Cerb allows.
+	Limit int
+	// Page of results to load. Functions that load every page themselves (e.g. FindAllGroups) ignore it.
+	Page int
+	// Expand replaces the default list of keys to expand. Use an empty, non-nil slice to expand nothing.
+	Expand []string
+	// Fields limits the keys returned for each result to reduce the payload size. Every key is returned when empty.
+	Fields []string
+}
+
+var sortTermPattern = regexp.MustCompile(`(^|\s)sort:\S+`)
+
+// applySearchOptions merges the first of opts into params, which hold the search function's defaults
+func applySearchOptions(params url.Values, opts []SearchOptions) {
+	if len(opts) == 0 {
+		return
+	}
+	o := opts[0]
+
+	q := params.Get("q")
+	if o.Query != "" {
+		q = strings.TrimSpace(q + " " + o.Query)
+	}
+	if len(o.Sort) > 0 {
+		fields := make([]string, len(o.Sort))
+		for i, s := range o.Sort {
+			fields[i] = s.Field
+			if s.Descending {
+				fields[i] = "-" + s.Field
+			}
+		}
+		q = strings.TrimSpace(sortTermPattern.ReplaceAllString(q, "") + " sort:" + strings.Join(fields, ","))
+	}
+	params.Set("q", q)
+
+	if o.Limit > 0 {
+		limit := o.Limit
+		if limit > maxSearchLimit {
+			limit = maxSearchLimit
+		}
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	if o.Page > 0 {
+		params.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.Expand != nil {
+		if len(o.Expand) == 0 {
+			params.Del("expand")
+		} else {
+			params.Set("expand", strings.Join(o.Expand, ","))
+		}
+	}
+	if len(o.Fields) > 0 {
+		params.Set("fields", strings.Join(o.Fields, ","))
+	}
+}
+
+// searchLimit returns the limit params will request
+func searchLimit(params url.Values) int {
+	limit, _ := strconv.Atoi(params.Get("limit"))
+	return limit
+}
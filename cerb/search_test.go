@@ -0,0 +1,42 @@
+package cerb
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestApplySearchOptions(t *testing.T) {
+	defaults := func() url.Values {
+		return url.Values{"q": {"status:o sort:-updated"}, "limit": {"25"}, "expand": {"group_"}}
+	}
+
+	tests := []struct {
+		name string
+		opts []SearchOptions
+		want url.Values
+	}{
+		{"no options", nil, defaults()},
+		{"query is added", []SearchOptions{{Query: "group.id:3"}},
+			url.Values{"q": {"status:o sort:-updated group.id:3"}, "limit": {"25"}, "expand": {"group_"}}},
+		{"sort replaces the default", []SearchOptions{{Sort: []SortField{{Field: "id"}, {Field: "created", Descending: true}}}},
+			url.Values{"q": {"status:o sort:id,-created"}, "limit": {"25"}, "expand": {"group_"}}},
+		{"limit", []SearchOptions{{Limit: 100}},
+			url.Values{"q": {"status:o sort:-updated"}, "limit": {"100"}, "expand": {"group_"}}},
+		{"limit is capped", []SearchOptions{{Limit: 1000}},
+			url.Values{"q": {"status:o sort:-updated"}, "limit": {"250"}, "expand": {"group_"}}},
+		{"empty expand removes it", []SearchOptions{{Expand: []string{}}},
+			url.Values{"q": {"status:o sort:-updated"}, "limit": {"25"}}},
+		{"page and fields", []SearchOptions{{Page: 2, Fields: []string{"id", "mask"}}},
+			url.Values{"q": {"status:o sort:-updated"}, "limit": {"25"}, "expand": {"group_"}, "page": {"2"}, "fields": {"id,mask"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := defaults()
+			applySearchOptions(params, tt.opts)
+			if params.Encode() != tt.want.Encode() {
+				t.Errorf("applySearchOptions() = %v, want %v", params, tt.want)
+			}
+		})
+	}
+}
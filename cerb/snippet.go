@@ -0,0 +1,247 @@
+package cerb
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Snippet is a canned response used by agents. Its content may contain placeholders such as `{{worker_first_name}}` which Cerb fills in from the record the snippet is used on. @see https://cerb.ai/docs/records/types/snippet/
+type Snippet struct {
+	ID           int    `json:"id"`
+	Title        string `json:"title"`
+	Content      string `json:"content"`
+	Context      string `json:"context"`        // Type of record the placeholders come from, e.g. cerberusweb.contexts.ticket. Empty for plain text.
+	OwnerContext string `json:"owner__context"` // e.g. cerberusweb.contexts.group
+	OwnerID      int    `json:"owner_id"`
+	TotalUses    int    `json:"total_uses"`
+	URL          string `json:"record_url"`
+	Updated      int    `json:"updated_at"`
+}
+
+// SearchSnippetsResponse is the response from the records/snippet/search.json endpoint
+type SearchSnippetsResponse struct {
+	Status  string    `json:"__status"`
+	Count   int       `json:"count"`
+	Limit   int       `json:"limit"`
+	Page    int       `json:"page"`
+	Results []Snippet `json:"results"`
+	Total   int       `json:"total"`
+	Version string    `json:"__version"`
+}
+
+// SnippetQuery filters snippets. Empty fields are ignored.
+type SnippetQuery struct {
+	Title   string // Matches titles containing this text
+	Tag     string // Matches snippets with this #hashtag in their title or content, e.g. "#c++"
+	GroupID int    // Matches snippets owned by this group
+}
+
+// SearchSnippets finds snippets matching the query. Cerb has no tags for snippets so, by convention, tags are #hashtags in the title or content and are matched locally after searching every page of results.
+func (c Cerberus) SearchSnippets(q SnippetQuery, opts ...SearchOptions) (*[]Snippet, error) {
+	var terms []string
+	if q.Title != "" {
+		terms = append(terms, `title:"*`+q.Title+`*"`)
+	}
+	if q.GroupID != 0 {
+		terms = append(terms, "owner.group:(id:"+strconv.Itoa(q.GroupID)+")")
+	}
+
+	limit := 250 // Maximum of 250 enforced by server
+	params := url.Values{}
+	params.Set("q", strings.Join(terms, " "))
+	params.Set("limit", strconv.Itoa(limit))
+	applySearchOptions(params, opts)
+
+	if q.Tag == "" {
+		var r SearchSnippetsResponse
+		err := c.performRequest(http.MethodGet, "records/snippet/search.json", params, nil, &r)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to search snippets: %v", err)
+		}
+
+		return &r.Results, nil
+	}
+
+	tag := snippetTagPattern(q.Tag)
+	snippets := []Snippet{}
+	searched := 0
+	for page := 0; ; page++ {
+		params.Set("page", strconv.Itoa(page))
+
+		var r SearchSnippetsResponse
+		err := c.performRequest(http.MethodGet, "records/snippet/search.json", params, nil, &r)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to search snippets on page %d: %v", page, err)
+		}
+
+		for _, s := range r.Results {
+			if tag.MatchString(s.Title) || tag.MatchString(s.Content) {
+				snippets = append(snippets, s)
+			}
+		}
+
+		searched += len(r.Results)
+		if len(r.Results) == 0 || searched >= r.Total {
+			break
+		}
+	}
+
+	return &snippets, nil
+}
+
+// snippetTagPattern matches the #hashtag in text. Punctuation may end a tag but not characters that could continue it, so #c doesn't match #c++.
+func snippetTagPattern(tag string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)(^|\s)#` + regexp.QuoteMeta(strings.TrimPrefix(tag, "#")) + `([^\w+#-]|$)`)
+}
+
+// GetSnippet loads the snippet with the given ID
+func (c Cerberus) GetSnippet(snippetID int) (*Snippet, error) {
+	var s Snippet
+	err := c.performRequest(http.MethodGet, "records/snippet/"+strconv.Itoa(snippetID)+".json", nil, nil, &s)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get snippet %d: %v", snippetID, err)
+	}
+
+	return &s, nil
+}
+
+// snippetForm converts the non-zero fields of s into the form used by the create and update endpoints
+func snippetForm(s Snippet) url.Values {
+	form := url.Values{}
+
+	if s.Title != "" {
+		form.Set("fields[title]", s.Title)
+	}
+	if s.Content != "" {
+		form.Set("fields[content]", s.Content)
+	}
+	if s.Context != "" {
+		form.Set("fields[context]", s.Context)
+	}
+	if s.OwnerContext != "" {
+		form.Set("fields[owner__context]", s.OwnerContext)
+		form.Set("fields[owner_id]", strconv.Itoa(s.OwnerID))
+	}
+
+	return form
+}
+
+// CreateSnippet creates a new snippet. Title, Content and the owner are required.
+func (c Cerberus) CreateSnippet(s Snippet) (*Snippet, error) {
+	var created Snippet
+	err := c.performRequest(http.MethodPost, "records/snippet/create.json", nil, snippetForm(s), &created)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create snippet %s: %v", s.Title, err)
+	}
+
+	return &created, nil
+}
+
+// UpdateSnippet updates the snippet with the non-zero fields of s. s.ID must be set.
+func (c Cerberus) UpdateSnippet(s Snippet) (*Snippet, error) {
+	var updated Snippet
+	err := c.performRequest(http.MethodPut, "records/snippet/"+strconv.Itoa(s.ID)+".json", nil, snippetForm(s), &updated)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update snippet %d: %v", s.ID, err)
+	}
+
+	return &updated, nil
+}
+
+// snippetPlaceholder matches `{{ name }}` and `{{ name|default('fallback') }}`, the only template expressions RenderSnippet can evaluate
+var snippetPlaceholder = regexp.MustCompile(`^\{\{\s*([a-zA-Z0-9_.]+)\s*(?:\|\s*default\(\s*(?:'([^']*)'|"([^"]*)")\s*\)\s*)?\}\}$`)
+
+// snippetTemplateTag matches any Twig expression `{{ ... }}` or tag `{% ... %}` in snippet content
+var snippetTemplateTag = regexp.MustCompile(`(?s)\{\{.*?\}\}|\{%.*?%\}`)
+
+// RenderSnippet previews what the snippet content will produce by filling in its placeholders from dict. Placeholders using Cerb's `default('...')` filter fall back to that value. Anything that can't be filled is left as-is and returned so callers can decide whether the snippet is safe to send: the keys of placeholders missing from dict, and the full text of expressions RenderSnippet can't evaluate such as other filters or `{% if %}` tags.
+func RenderSnippet(content string, dict map[string]string) (string, []string) {
+	var missing []string
+	seen := map[string]bool{}
+	report := func(s string) {
+		if !seen[s] {
+			seen[s] = true
+			missing = append(missing, s)
+		}
+	}
+
+	rendered := snippetTemplateTag.ReplaceAllStringFunc(content, func(tag string) string {
+		m := snippetPlaceholder.FindStringSubmatch(tag)
+		if m == nil {
+			report(tag)
+			return tag
+		}
+		key := m[1]
+
+		if v, ok := dict[key]; ok && v != "" {
+			return v
+		}
+
+		if strings.Contains(tag, "default(") {
+			return m[2] + m[3]
+		}
+
+		report(key)
+		return tag
+	})
+
+	return rendered, missing
+}
+
+// TicketDictionary builds the placeholders for a ticket, e.g. `{{ticket_mask}}` when prefix is "ticket_"
+func TicketDictionary(prefix string, t CerberusTicket) map[string]string {
+	return map[string]string{
+		prefix + "id":                           strconv.Itoa(t.ID),
+		prefix + "mask":                         t.Mask,
+		prefix + "subject":                      t.Subject,
+		prefix + "status":                       t.Status,
+		prefix + "url":                          t.URL,
+		prefix + "initial_message_sender_email": t.Email,
+	}
+}
+
+// ContactDictionary builds the placeholders for a contact, e.g. `{{contact_first_name}}` when prefix is "contact_"
+func ContactDictionary(prefix string, ct Contact) map[string]string {
+	return map[string]string{
+		prefix + "id":         strconv.Itoa(ct.ID),
+		prefix + "first_name": ct.FirstName,
+		prefix + "last_name":  ct.LastName,
+		prefix + "name":       strings.TrimSpace(ct.FirstName + " " + ct.LastName),
+		prefix + "title":      ct.Title,
+		prefix + "email":      ct.Email,
+		prefix + "org_name":   ct.OrgName,
+	}
+}
+
+// WorkerDictionary builds the placeholders for a worker, e.g. `{{worker_first_name}}` when prefix is "worker_"
+func WorkerDictionary(prefix string, w Worker) map[string]string {
+	return map[string]string{
+		prefix + "id":              strconv.Itoa(w.ID),
+		prefix + "first_name":      w.FirstName,
+		prefix + "last_name":       w.LastName,
+		prefix + "full_name":       w.FullName,
+		prefix + "title":           w.Title,
+		prefix + "email_address":   w.Email,
+		prefix + "at_mention_name": w.MentionName,
+	}
+}
+
+// MergeDictionaries combines placeholder dictionaries. Later dictionaries win when keys collide.
+func MergeDictionaries(dicts ...map[string]string) map[string]string {
+	merged := map[string]string{}
+	for _, d := range dicts {
+		for k, v := range d {
+			merged[k] = v
+		}
+	}
+	return merged
+}
@@ -0,0 +1,68 @@
+package cerb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRenderSnippet(t *testing.T) {
+	dict := map[string]string{"first_name": "Jane", "empty": ""}
+
+	tests := []struct {
+		name        string
+		content     string
+		want        string
+		wantMissing []string
+	}{
+		{"plain text", "Hello", "Hello", nil},
+		{"placeholder", "Hi {{first_name}}!", "Hi Jane!", nil},
+		{"spaces", "Hi {{ first_name }}!", "Hi Jane!", nil},
+		{"default used when missing", "Hi {{name|default('there')}}", "Hi there", nil},
+		{"default used when empty", `Hi {{ empty | default("there") }}`, "Hi there", nil},
+		{"default unused when set", "Hi {{first_name|default('there')}}", "Hi Jane", nil},
+		{"missing key", "Hi {{last_name}} {{last_name}}", "Hi {{last_name}} {{last_name}}", []string{"last_name"}},
+		{"other filter", "Hi {{first_name|upper}}", "Hi {{first_name|upper}}", []string{"{{first_name|upper}}"}},
+		{"statement", "{% if first_name %}Hi{% endif %} {{first_name}}",
+			"{% if first_name %}Hi{% endif %} Jane", []string{"{% if first_name %}", "{% endif %}"}},
+		{"expression across lines", "{{ first_name\n ~ 'x' }}", "{{ first_name\n ~ 'x' }}", []string{"{{ first_name\n ~ 'x' }}"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, missing := RenderSnippet(tt.content, dict)
+			if got != tt.want {
+				t.Errorf("RenderSnippet() = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(missing, tt.wantMissing) {
+				t.Errorf("RenderSnippet() missing = %q, want %q", missing, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestSnippetTagPattern(t *testing.T) {
+	tests := []struct {
+		tag  string
+		text string
+		want bool
+	}{
+		{"billing", "#billing", true},
+		{"#billing", "About #Billing here", true},
+		{"billing", "About #billing.", true},
+		{"billing", "(see #billing, #refunds)", true},
+		{"billing", "#billing-faq", false},
+		{"billing", "#billings", false},
+		{"billing", "no#billing", false},
+		{"c", "#c++", false},
+		{"c++", "Using #c++ today", true},
+		{"c++", "#c++.", true},
+		{"c#", "#c#", true},
+		{"c", "#c#", false},
+	}
+
+	for _, tt := range tests {
+		if got := snippetTagPattern(tt.tag).MatchString(tt.text); got != tt.want {
+			t.Errorf("snippetTagPattern(%q).MatchString(%q) = %v, want %v", tt.tag, tt.text, got, tt.want)
+		}
+	}
+}
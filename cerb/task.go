@@ -0,0 +1,172 @@
+package cerb
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Task represents a Cerb task, e.g. engineering follow-up work for a ticket. @see https://cerb.ai/docs/records/types/task/
+type Task struct {
+	ID         int    `json:"id"`
+	Title      string `json:"title"`
+	Status     string `json:"status"`     // [o]pen, [w]aiting, [c]losed
+	Importance int    `json:"importance"` // 0-100
+	OwnerID    int    `json:"owner_id"`
+	Due        int    `json:"due"`
+	Completed  int    `json:"completed"`
+	Reopen     int    `json:"reopen"`
+	URL        string `json:"record_url"`
+	Created    int    `json:"created"`
+	Updated    int    `json:"updated"`
+}
+
+// SearchTasksResponse is the response from the records/task/search.json endpoint
+type SearchTasksResponse struct {
+	Status  string `json:"__status"`
+	Count   int    `json:"count"`
+	Limit   int    `json:"limit"`
+	Page    int    `json:"page"`
+	Results []Task `json:"results"`
+	Total   int    `json:"total"`
+	Version string `json:"__version"`
+}
+
+// taskForm converts the non-zero fields of t into the form used by the create and update endpoints
+func taskForm(t Task) url.Values {
+	form := url.Values{}
+
+	if t.Title != "" {
+		form.Set("fields[title]", t.Title)
+	}
+	if t.Status != "" {
+		form.Set("fields[status]", t.Status)
+	}
+	if t.Importance != 0 {
+		form.Set("fields[importance]", strconv.Itoa(t.Importance))
+	}
+	if t.OwnerID != 0 {
+		form.Set("fields[owner_id]", strconv.Itoa(t.OwnerID))
+	}
+	if t.Due != 0 {
+		form.Set("fields[due]", strconv.Itoa(t.Due))
+	}
+	if t.Reopen != 0 {
+		form.Set("fields[reopen]", strconv.Itoa(t.Reopen))
+	}
+
+	return form
+}
+
+// CreateTask creates a new task. Title is required and Status defaults to [o]pen. The optional links are record `context:id` tuples (e.g. "ticket:123") that the task is linked to.
+func (c Cerberus) CreateTask(t Task, links ...string) (*Task, error) {
+	if t.Status == "" {
+		t.Status = "o"
+	}
+
+	form := taskForm(t)
+	for _, link := range links {
+		form.Add("fields[links][]", link)
+	}
+
+	var created Task
+	err := c.performRequest(http.MethodPost, "records/task/create.json", nil, form, &created)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create task %s: %v", t.Title, err)
+	}
+
+	return &created, nil
+}
+
+// CreateTaskForTicket creates a new task linked to the given ticket so the ticket shows its follow-up work
+func (c Cerberus) CreateTaskForTicket(ticketID int, t Task) (*Task, error) {
+	return c.CreateTask(t, "ticket:"+strconv.Itoa(ticketID))
+}
+
+// GetTask loads the task with the given ID
+func (c Cerberus) GetTask(taskID int) (*Task, error) {
+	var t Task
+	err := c.performRequest(http.MethodGet, "records/task/"+strconv.Itoa(taskID)+".json", nil, nil, &t)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get task %d: %v", taskID, err)
+	}
+
+	return &t, nil
+}
+
+// UpdateTask updates the task with the non-zero fields of t. t.ID must be set.
+func (c Cerberus) UpdateTask(t Task) (*Task, error) {
+	return c.updateTask(t.ID, taskForm(t))
+}
+
+func (c Cerberus) updateTask(taskID int, form url.Values) (*Task, error) {
+	var updated Task
+	err := c.performRequest(http.MethodPut, "records/task/"+strconv.Itoa(taskID)+".json", nil, form, &updated)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update task %d: %v", taskID, err)
+	}
+
+	return &updated, nil
+}
+
+// SearchTasks finds tasks matching the given Cerb search query, e.g. `status:o owner.id:3`
+func (c Cerberus) SearchTasks(query string, opts ...SearchOptions) (*[]Task, error) {
+	limit := 250 // If you need pagination imitate ListOpenTickets
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(limit))
+	applySearchOptions(params, opts)
+
+	var r SearchTasksResponse
+	err := c.performRequest(http.MethodGet, "records/task/search.json", params, nil, &r)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to search tasks: %v", err)
+	}
+
+	return &r.Results, nil
+}
+
+// FindTasksForTicket lists the tasks linked to the given ticket
+func (c Cerberus) FindTasksForTicket(ticketID int, opts ...SearchOptions) (*[]Task, error) {
+	return c.SearchTasks("links.ticket:(id:"+strconv.Itoa(ticketID)+")", opts...)
+}
+
+// CompleteTask closes the task. Cerb records the time it was completed.
+func (c Cerberus) CompleteTask(taskID int) error {
+	_, err := c.UpdateTask(Task{ID: taskID, Status: "c"})
+	return err
+}
+
+// AssignTask makes the worker the owner of the task. Use a workerID of 0 to unassign it.
+func (c Cerberus) AssignTask(taskID int, workerID int) error {
+	form := url.Values{}
+	form.Set("fields[owner_id]", strconv.Itoa(workerID))
+
+	_, err := c.updateTask(taskID, form)
+	return err
+}
+
+// SetTaskDue sets when the task is due. Use the zero time to clear the due date.
+func (c Cerberus) SetTaskDue(taskID int, due time.Time) error {
+	timestamp := 0
+	if !due.IsZero() {
+		timestamp = int(due.Unix())
+	}
+
+	form := url.Values{}
+	form.Set("fields[due]", strconv.Itoa(timestamp))
+
+	_, err := c.updateTask(taskID, form)
+	return err
+}
+
+// LinkTaskToTicket links an existing task to the ticket
+func (c Cerberus) LinkTaskToTicket(taskID int, ticketID int) error {
+	return c.LinkRecords(RecordRef{Context: "task", ID: taskID}, RecordRef{Context: "ticket", ID: ticketID})
+}
@@ -0,0 +1,336 @@
+package cerb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SyncTickets keeps a local copy of ticket metadata up to date by paging through the tickets changed since the last sync, the same way Watch does: each page is searched from the latest `updated` timestamp synced so tickets changed mid-sync can't push unsynced tickets onto a page already read. The checkpoint is saved to the store after every page so an interrupted sync resumes from the last completed page. Writes must be idempotent since the last page may be written again after an interruption.
+
+// TicketStore is where SyncTickets writes tickets
+type TicketStore interface {
+	// PutTicket inserts or replaces the ticket
+	PutTicket(t CerberusTicket) error
+	// DeleteTicket removes the ticket. Deleting a ticket that isn't stored is not an error.
+	DeleteTicket(ticketID int) error
+	// TicketIDs lists every stored ticket, used to find tickets that were permanently deleted in Cerb
+	TicketIDs() ([]int, error)
+	// LoadCheckpoint returns the saved checkpoint, or nil when nothing has been synced yet
+	LoadCheckpoint() (*SyncCheckpoint, error)
+	SaveCheckpoint(cp SyncCheckpoint) error
+}
+
+// SyncCheckpoint records how far a sync got
+type SyncCheckpoint struct {
+	Updated int   `json:"updated"`  // The latest `updated` timestamp synced
+	SeenIDs []int `json:"seen_ids"` // Tickets already synced with an `updated` equal to Updated
+}
+
+// SyncOptions controls which tickets SyncTickets copies
+type SyncOptions struct {
+	// Query restricts the tickets synced, e.g. `group.id:3`. Empty syncs every ticket.
+	Query string
+	// Since is where to start when the store has no checkpoint. Defaults to the beginning of time, i.e. a full export.
+	Since time.Time
+	// Reconcile compares every stored ticket ID against Cerb after syncing and deletes the ones that no longer exist. Permanently deleted tickets can't be found any other way but this lists every ticket so it's slow on large instances. When Query is set, stored tickets that no longer match it (e.g. moved to another group) are deleted too.
+	Reconcile bool
+}
+
+// SyncResult counts what SyncTickets did
+type SyncResult struct {
+	Written    int
+	Deleted    int
+	Checkpoint SyncCheckpoint
+}
+
+// SyncTickets copies the tickets changed since the store's checkpoint into the store. Tickets whose status is deleted are removed from the store.
+func (c Cerberus) SyncTickets(ctx context.Context, store TicketStore, opts SyncOptions) (*SyncResult, error) {
+	cp, err := store.LoadCheckpoint()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load sync checkpoint: %v", err)
+	}
+	if cp == nil {
+		cp = &SyncCheckpoint{}
+		if !opts.Since.IsZero() {
+			cp.Updated = int(opts.Since.Unix())
+		}
+	}
+
+	result := SyncResult{}
+	mark := newHighWaterMark(cp.Updated, cp.SeenIDs)
+
+	err = c.eachUpdatedTicket(ctx, opts.Query, mark, func(t CerberusTicket) error {
+		var err error
+		if isDeletedStatus(t.Status) {
+			err = store.DeleteTicket(t.ID)
+			result.Deleted++
+		} else {
+			err = store.PutTicket(t)
+			result.Written++
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to store ticket %d: %v", t.ID, err)
+		}
+		return nil
+	}, func() error {
+		cp.Updated = mark.updated
+		cp.SeenIDs = mark.seenIDs()
+
+		err := store.SaveCheckpoint(*cp)
+		if err != nil {
+			return fmt.Errorf("Failed to save sync checkpoint: %v", err)
+		}
+		result.Checkpoint = *cp
+		return nil
+	})
+	if err != nil {
+		return &result, err
+	}
+
+	if opts.Reconcile {
+		deleted, err := c.reconcileDeletedTickets(opts.Query, store)
+		result.Deleted += deleted
+		if err != nil {
+			return &result, err
+		}
+	}
+
+	return &result, nil
+}
+
+// reconcileDeletedTickets deletes the stored tickets that aren't among the tickets matching query. The matching IDs are read in ID order (see FindTicketIDs) so tickets changing during the listing can't be missed and deleted by mistake.
+func (c Cerberus) reconcileDeletedTickets(query string, store TicketStore) (int, error) {
+	remote, err := c.FindTicketIDs(query)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to list tickets to reconcile: %v", err)
+	}
+
+	exists := make(map[int]bool, len(remote))
+	for _, id := range remote {
+		exists[id] = true
+	}
+
+	local, err := store.TicketIDs()
+	if err != nil {
+		return 0, fmt.Errorf("Failed to list stored tickets to reconcile: %v", err)
+	}
+
+	deleted := 0
+	for _, id := range local {
+		if exists[id] {
+			continue
+		}
+
+		err = store.DeleteTicket(id)
+		if err != nil {
+			return deleted, fmt.Errorf("Failed to delete ticket %d from store: %v", id, err)
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// isDeletedStatus reports whether the ticket status means it was deleted in Cerb
+func isDeletedStatus(status string) bool {
+	return status == "d" || strings.EqualFold(status, "deleted")
+}
+
+// JSONLinesStore is a TicketStore that appends every change as a line of JSON to a file, with the checkpoint kept alongside it in a ".checkpoint" file. Replaying the file with Tickets gives the current state. A line left incomplete by a crash is removed when the file is next opened; the checkpoint never covers it so the ticket is synced again.
+type JSONLinesStore struct {
+	path string
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// jsonLinesEntry is one line of a JSONLinesStore file
+type jsonLinesEntry struct {
+	Op     string          `json:"op"` // "put" or "delete"
+	ID     int             `json:"id"`
+	Ticket *CerberusTicket `json:"ticket,omitempty"`
+}
+
+// NewJSONLinesStore opens (or creates) the JSON lines file at path. Call Close when done.
+func NewJSONLinesStore(path string) (*JSONLinesStore, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("Error opening ticket store %s: %v", path, err)
+	}
+
+	err = truncatePartialLine(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("Error recovering ticket store %s: %v", path, err)
+	}
+
+	return &JSONLinesStore{path: path, file: f}, nil
+}
+
+// truncatePartialLine cuts the file after its last newline so an append interrupted part way through doesn't corrupt the next line written
+func truncatePartialLine(f *os.File) error {
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	end := info.Size()
+	buf := make([]byte, 64*1024)
+	for end > 0 {
+		start := end - int64(len(buf))
+		if start < 0 {
+			start = 0
+		}
+		chunk := buf[:end-start]
+
+		_, err = f.ReadAt(chunk, start)
+		if err != nil {
+			return err
+		}
+
+		if i := bytes.LastIndexByte(chunk, '\n'); i >= 0 {
+			end = start + int64(i) + 1
+			break
+		}
+		end = start
+	}
+
+	if end == info.Size() {
+		return nil
+	}
+	return f.Truncate(end)
+}
+
+// Close closes the underlying file
+func (s *JSONLinesStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+func (s *JSONLinesStore) append(e jsonLinesEntry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("Error encoding ticket %d: %v", e.ID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.file.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("Error writing ticket %d to %s: %v", e.ID, s.path, err)
+	}
+
+	return nil
+}
+
+// PutTicket appends the ticket to the file
+func (s *JSONLinesStore) PutTicket(t CerberusTicket) error {
+	return s.append(jsonLinesEntry{Op: "put", ID: t.ID, Ticket: &t})
+}
+
+// DeleteTicket appends a deletion of the ticket to the file
+func (s *JSONLinesStore) DeleteTicket(ticketID int) error {
+	return s.append(jsonLinesEntry{Op: "delete", ID: ticketID})
+}
+
+// Tickets replays the file and returns the current version of every stored ticket, keyed by ID
+func (s *JSONLinesStore) Tickets() (map[int]CerberusTicket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("Error opening ticket store %s: %v", s.path, err)
+	}
+	defer f.Close()
+
+	tickets := map[int]CerberusTicket{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		var e jsonLinesEntry
+		err = json.Unmarshal(scanner.Bytes(), &e)
+		if err != nil {
+			return nil, fmt.Errorf("Error decoding line %d of %s: %v", line, s.path, err)
+		}
+
+		if e.Op == "delete" || e.Ticket == nil {
+			delete(tickets, e.ID)
+		} else {
+			tickets[e.ID] = *e.Ticket
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Error reading %s: %v", s.path, err)
+	}
+
+	return tickets, nil
+}
+
+// TicketIDs lists every stored ticket
+func (s *JSONLinesStore) TicketIDs() ([]int, error) {
+	tickets, err := s.Tickets()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(tickets))
+	for id := range tickets {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	return ids, nil
+}
+
+// LoadCheckpoint reads the checkpoint file, returning nil when nothing has been synced yet
+func (s *JSONLinesStore) LoadCheckpoint() (*SyncCheckpoint, error) {
+	data, err := ioutil.ReadFile(s.path + ".checkpoint")
+
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error reading checkpoint for %s: %v", s.path, err)
+	}
+
+	var cp SyncCheckpoint
+	err = json.Unmarshal(data, &cp)
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding checkpoint for %s: %v", s.path, err)
+	}
+
+	return &cp, nil
+}
+
+// SaveCheckpoint syncs the ticket file to disk and then atomically replaces the checkpoint file, so the checkpoint never gets ahead of the tickets it covers
+func (s *JSONLinesStore) SaveCheckpoint(cp SyncCheckpoint) error {
+	s.mu.Lock()
+	err := s.file.Sync()
+	s.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("Error syncing %s: %v", s.path, err)
+	}
+
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("Error encoding checkpoint: %v", err)
+	}
+
+	return writeFileAtomic(s.path+".checkpoint", data)
+}
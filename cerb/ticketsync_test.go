@@ -0,0 +1,64 @@
+package cerb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJSONLinesStoreRecoversPartialLine(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ticketsync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "tickets.jsonl")
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"empty", "", ""},
+		{"complete lines", `{"op":"delete","id":1}` + "\n", `{"op":"delete","id":1}` + "\n"},
+		{"partial last line", `{"op":"delete","id":1}` + "\n" + `{"op":"put","id":2,"tick`, `{"op":"delete","id":1}` + "\n"},
+		{"only a partial line", `{"op":"put","id":2`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ioutil.WriteFile(path, []byte(tt.content), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			store, err := NewJSONLinesStore(path)
+			if err != nil {
+				t.Fatalf("NewJSONLinesStore() error = %v", err)
+			}
+			defer store.Close()
+
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("file after opening = %q, want %q", data, tt.want)
+			}
+
+			err = store.PutTicket(CerberusTicket{ID: 3, Subject: "Hello"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tickets, err := store.Tickets()
+			if err != nil {
+				t.Fatalf("Tickets() error = %v", err)
+			}
+			if len(tickets) != 1 || tickets[3].Subject != "Hello" {
+				t.Errorf("Tickets() = %+v, want only ticket 3", tickets)
+			}
+		})
+	}
+}
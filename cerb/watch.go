@@ -0,0 +1,454 @@
+package cerb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Watch polls records/ticket/search.json for tickets updated since a high-water mark and turns what it finds into events. Cerb's `updated` timestamps have one second resolution so the IDs already emitted at the high-water mark are remembered to avoid emitting them twice. The cursor holding this state is saved to a CursorStore after every poll so a restarted watcher picks up where it left off.
+
+// defaultWatchInterval is how often Watch polls when WatchOptions.Interval isn't set
+const defaultWatchInterval = 30 * time.Second
+
+// defaultWatchMaxStatuses is how many ticket statuses WatchCursor remembers when WatchOptions.MaxStatuses isn't set
+const defaultWatchMaxStatuses = 10000
+
+// TicketEventType describes what happened to a ticket
+type TicketEventType string
+
+// Ticket event types emitted by Watch
+const (
+	TicketEventCreated       TicketEventType = "created"
+	TicketEventUpdated       TicketEventType = "updated"
+	TicketEventStatusChanged TicketEventType = "status_changed"
+)
+
+// TicketEvent is a change to a ticket found by Watch
+type TicketEvent struct {
+	Type           TicketEventType
+	Ticket         CerberusTicket
+	PreviousStatus string // Set for TicketEventStatusChanged
+}
+
+// WatchCursor is the state Watch persists between polls
+type WatchCursor struct {
+	Updated  int            `json:"updated"`  // High-water mark: the latest `updated` timestamp seen
+	SeenIDs  []int          `json:"seen_ids"` // Tickets already emitted with an `updated` equal to the high-water mark
+	Statuses map[int]string `json:"statuses"` // Last known status of each ticket, used to detect status changes. Deleted tickets are dropped and closed ones are pruned past WatchOptions.MaxStatuses.
+}
+
+// CursorStore persists a WatchCursor
+type CursorStore interface {
+	// LoadCursor returns the saved cursor, or nil when nothing has been saved yet
+	LoadCursor() (*WatchCursor, error)
+	SaveCursor(cursor WatchCursor) error
+}
+
+// WatchOptions controls what Watch polls for and how often
+type WatchOptions struct {
+	// Query restricts the tickets watched, e.g. `group.id:3`. Empty watches every ticket.
+	Query string
+	// Interval between polls. Defaults to 30 seconds.
+	Interval time.Duration
+	// Store persists the cursor. Defaults to a MemoryCursorStore, which doesn't survive restarts.
+	Store CursorStore
+	// Since is where to start when Store has no saved cursor. Defaults to now.
+	Since time.Time
+	// MaxStatuses limits how many ticket statuses the cursor remembers. Once it's exceeded closed tickets are forgotten, so reopening one of them is reported as TicketEventUpdated rather than TicketEventStatusChanged. Open and waiting tickets are always remembered. Defaults to 10,000; negative disables the limit.
+	MaxStatuses int
+}
+
+// Watch polls Cerb for ticket changes until ctx is cancelled, emitting them in order of when they were updated. Errors are sent on the second channel without stopping the watcher; it has a small buffer and errors are dropped if it's full. Both channels are closed when ctx is cancelled.
+func (c Cerberus) Watch(ctx context.Context, opts WatchOptions) (<-chan TicketEvent, <-chan error) {
+	events := make(chan TicketEvent)
+	errs := make(chan error, 16)
+
+	if opts.Interval <= 0 {
+		opts.Interval = defaultWatchInterval
+	}
+	if opts.Store == nil {
+		opts.Store = &MemoryCursorStore{}
+	}
+	if opts.Since.IsZero() {
+		opts.Since = time.Now()
+	}
+	if opts.MaxStatuses == 0 {
+		opts.MaxStatuses = defaultWatchMaxStatuses
+	}
+
+	report := func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}
+
+	go func() {
+		defer close(events)
+		defer close(errs)
+
+		cursor, err := opts.Store.LoadCursor()
+		if err != nil {
+			report(fmt.Errorf("Failed to load watch cursor: %v", err))
+			return
+		}
+		if cursor == nil {
+			cursor = &WatchCursor{Updated: int(opts.Since.Unix())}
+		}
+		if cursor.Statuses == nil {
+			cursor.Statuses = map[int]string{}
+		}
+
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+
+		for {
+			err := c.pollTickets(ctx, opts.Query, cursor, opts.MaxStatuses, events)
+			if err != nil && ctx.Err() == nil {
+				report(err)
+			}
+
+			err = opts.Store.SaveCursor(*cursor)
+			if err != nil {
+				report(fmt.Errorf("Failed to save watch cursor: %v", err))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return events, errs
+}
+
+// pollTickets emits an event for every ticket updated since the cursor, advancing the cursor as it goes
+func (c Cerberus) pollTickets(ctx context.Context, query string, cursor *WatchCursor, maxStatuses int, events chan<- TicketEvent) error {
+	since := cursor.Updated
+	mark := newHighWaterMark(cursor.Updated, cursor.SeenIDs)
+
+	err := c.eachUpdatedTicket(ctx, query, mark, func(t CerberusTicket) error {
+		e := TicketEvent{Type: TicketEventUpdated, Ticket: t}
+		previous, known := cursor.Statuses[t.ID]
+		switch {
+		case !known && t.Created >= since:
+			e.Type = TicketEventCreated
+		case known && previous != t.Status:
+			e.Type = TicketEventStatusChanged
+			e.PreviousStatus = previous
+		}
+
+		select {
+		case events <- e:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if isDeletedStatus(t.Status) {
+			delete(cursor.Statuses, t.ID)
+		} else {
+			cursor.Statuses[t.ID] = t.Status
+		}
+		return nil
+	}, nil)
+
+	cursor.Updated = mark.updated
+	cursor.SeenIDs = mark.seenIDs()
+	pruneStatuses(cursor.Statuses, maxStatuses)
+
+	return err
+}
+
+// pruneStatuses forgets closed tickets once more than max statuses are remembered. Open and waiting tickets are always kept so their status changes are still reported.
+func pruneStatuses(statuses map[int]string, max int) {
+	if max <= 0 || len(statuses) <= max {
+		return
+	}
+
+	for id, status := range statuses {
+		if ticketStatusCode(status) == "c" {
+			delete(statuses, id)
+		}
+	}
+}
+
+// highWaterMark tracks how far a scan of updated tickets has got. Cerb's `updated` timestamps have one second resolution so the IDs already processed at the latest timestamp are remembered too.
+type highWaterMark struct {
+	updated int
+	seen    map[int]bool
+}
+
+func newHighWaterMark(updated int, seenIDs []int) *highWaterMark {
+	m := &highWaterMark{updated: updated, seen: map[int]bool{}}
+	for _, id := range seenIDs {
+		m.seen[id] = true
+	}
+	return m
+}
+
+// isNew reports whether t hasn't been processed yet
+func (m *highWaterMark) isNew(t CerberusTicket) bool {
+	return t.Updated > m.updated || (t.Updated == m.updated && !m.seen[t.ID])
+}
+
+// add records t as processed
+func (m *highWaterMark) add(t CerberusTicket) {
+	if t.Updated > m.updated {
+		m.updated = t.Updated
+		m.seen = map[int]bool{}
+	}
+	m.seen[t.ID] = true
+}
+
+// seenIDs returns the IDs processed at the latest timestamp, sorted
+func (m *highWaterMark) seenIDs() []int {
+	ids := make([]int, 0, len(m.seen))
+	for id := range m.seen {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// eachUpdatedTicket calls fn for every ticket updated since mark that hasn't been processed yet, oldest update first, and calls afterPage (when set) once each page is done.
+//
+// Pages are read by position rather than by number: each search starts again from the high-water mark and reads its first page. A ticket updated mid-scan moves to the end of the sort order, so paging by number would shift an unread ticket onto a page that was already read and skip it. When a whole page shares the high-water mark's timestamp the rest of that second is read by ID instead, then the scan carries on from the next second.
+func (c Cerberus) eachUpdatedTicket(ctx context.Context, query string, mark *highWaterMark, fn func(t CerberusTicket) error, afterPage func() error) error {
+	process := func(r *CerberusTicketSearchResults) error {
+		for _, t := range r.Results {
+			if !mark.isNew(t) {
+				continue
+			}
+
+			err := fn(t)
+			if err != nil {
+				return err
+			}
+			mark.add(t)
+		}
+
+		if afterPage != nil {
+			return afterPage()
+		}
+		return nil
+	}
+
+	since := mark.updated
+	drained := false // Every ticket updated during the second `since` has been read
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		from := since
+		if drained {
+			from++
+		}
+
+		r, err := c.searchUpdatedTickets(query, from)
+		if err != nil {
+			return err
+		}
+
+		err = process(r)
+		if err != nil {
+			return err
+		}
+
+		if len(r.Results) < r.Limit || r.Total <= r.Limit {
+			return nil
+		}
+
+		if mark.updated > since {
+			since = mark.updated
+			drained = false
+			continue
+		}
+
+		// The whole page was updated during the second `since`
+		afterID := r.Results[len(r.Results)-1].ID
+		for {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			r, err = c.searchTicketsUpdatedAt(query, since, afterID)
+			if err != nil {
+				return err
+			}
+
+			err = process(r)
+			if err != nil {
+				return err
+			}
+
+			if len(r.Results) < r.Limit {
+				break
+			}
+			afterID = r.Results[len(r.Results)-1].ID
+		}
+		drained = true
+	}
+}
+
+// searchUpdatedTickets loads the first page of the tickets updated at or after since, oldest update first
+func (c Cerberus) searchUpdatedTickets(query string, since int) (*CerberusTicketSearchResults, error) {
+	from := time.Unix(int64(since), 0).UTC().Format("2006-01-02 15:04:05 UTC")
+	r, err := c.searchTicketsForSync(query + ` updated:"` + from + ` to now" sort:updated,id`)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to search tickets updated since %s: %v", from, err)
+	}
+
+	return r, nil
+}
+
+// searchTicketsUpdatedAt loads the first page of the tickets updated during the second at with an ID above afterID, in ID order
+func (c Cerberus) searchTicketsUpdatedAt(query string, at int, afterID int) (*CerberusTicketSearchResults, error) {
+	when := time.Unix(int64(at), 0).UTC().Format("2006-01-02 15:04:05 UTC")
+	r, err := c.searchTicketsForSync(query + ` updated:"` + when + ` to ` + when + `" id:>` + strconv.Itoa(afterID) + ` sort:id`)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to search tickets updated at %s: %v", when, err)
+	}
+
+	return r, nil
+}
+
+func (c Cerberus) searchTicketsForSync(query string) (*CerberusTicketSearchResults, error) {
+	limit := 250 // Maximum of 250 enforced by server
+	params := url.Values{}
+	params.Set("q", strings.TrimSpace(query))
+	params.Set("page", "0")
+	params.Set("limit", strconv.Itoa(limit))
+	params.Set("expand", "initial_message_sender_")
+
+	var r CerberusTicketSearchResults
+	err := c.performRequest(http.MethodGet, "records/ticket/search.json", params, nil, &r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.Limit = limit // Page and Limit in response are incorrect
+	return &r, nil
+}
+
+// MemoryCursorStore keeps the cursor in memory. It's the default for Watch and is lost when the process exits.
+type MemoryCursorStore struct {
+	mu     sync.Mutex
+	cursor *WatchCursor
+}
+
+// LoadCursor returns the saved cursor, or nil when nothing has been saved yet
+func (m *MemoryCursorStore) LoadCursor() (*WatchCursor, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cursor == nil {
+		return nil, nil
+	}
+
+	cursor := m.cursor.copy()
+	return &cursor, nil
+}
+
+// SaveCursor keeps a copy of the cursor in memory
+func (m *MemoryCursorStore) SaveCursor(cursor WatchCursor) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	saved := cursor.copy()
+	m.cursor = &saved
+	return nil
+}
+
+// copy returns a deep copy of the cursor so the store and the watcher never share its slice and map
+func (w WatchCursor) copy() WatchCursor {
+	cursor := WatchCursor{Updated: w.Updated}
+	if w.SeenIDs != nil {
+		cursor.SeenIDs = append([]int{}, w.SeenIDs...)
+	}
+	if w.Statuses != nil {
+		cursor.Statuses = make(map[int]string, len(w.Statuses))
+		for id, status := range w.Statuses {
+			cursor.Statuses[id] = status
+		}
+	}
+	return cursor
+}
+
+// FileCursorStore saves the cursor as JSON in a file so a watcher survives restarts
+type FileCursorStore struct {
+	Path string
+}
+
+// LoadCursor reads the cursor from the file, returning nil when the file doesn't exist yet
+func (f FileCursorStore) LoadCursor() (*WatchCursor, error) {
+	data, err := ioutil.ReadFile(f.Path)
+
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error reading cursor from %s: %v", f.Path, err)
+	}
+
+	var cursor WatchCursor
+	err = json.Unmarshal(data, &cursor)
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding cursor from %s: %v", f.Path, err)
+	}
+
+	return &cursor, nil
+}
+
+// SaveCursor writes the cursor to the file. It's written to a temporary file first and renamed so a crash never leaves a partial cursor behind.
+func (f FileCursorStore) SaveCursor(cursor WatchCursor) error {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return fmt.Errorf("Error encoding cursor: %v", err)
+	}
+
+	return writeFileAtomic(f.Path, data)
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it into place
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("Error creating temporary file for %s: %v", path, err)
+	}
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Close()
+	} else {
+		tmp.Close()
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("Error writing %s: %v", path, err)
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("Error replacing %s: %v", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package cerb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMemoryCursorStoreCopiesCursor(t *testing.T) {
+	var store MemoryCursorStore
+
+	cursor := WatchCursor{Updated: 100, SeenIDs: []int{1, 2}, Statuses: map[int]string{1: "o"}}
+	if err := store.SaveCursor(cursor); err != nil {
+		t.Fatal(err)
+	}
+
+	// Changing the saved cursor mustn't change the stored one
+	cursor.SeenIDs[0] = 9
+	cursor.Statuses[1] = "c"
+	cursor.Statuses[2] = "w"
+
+	loaded, err := store.LoadCursor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := WatchCursor{Updated: 100, SeenIDs: []int{1, 2}, Statuses: map[int]string{1: "o"}}
+	if !reflect.DeepEqual(*loaded, want) {
+		t.Fatalf("LoadCursor() = %+v, want %+v", *loaded, want)
+	}
+
+	// Nor should changing a loaded cursor
+	loaded.SeenIDs[1] = 9
+	loaded.Statuses[1] = "d"
+
+	again, err := store.LoadCursor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*again, want) {
+		t.Errorf("LoadCursor() after changing a loaded cursor = %+v, want %+v", *again, want)
+	}
+}
@@ -0,0 +1,64 @@
+package cerb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Workers watching a record are notified about every change to it. Like links, watchers are managed through a field on the record itself: a worker ID adds a watcher and a negative worker ID removes one.
+
+// AddWatchers makes the workers watchers of the record, e.g. RecordRef{"ticket", 123}
+func (c Cerberus) AddWatchers(record RecordRef, workerIDs ...int) error {
+	return c.updateWatchers(record, workerIDs, "")
+}
+
+// RemoveWatchers stops the workers watching the record
+func (c Cerberus) RemoveWatchers(record RecordRef, workerIDs ...int) error {
+	return c.updateWatchers(record, workerIDs, "-")
+}
+
+func (c Cerberus) updateWatchers(record RecordRef, workerIDs []int, prefix string) error {
+	if len(workerIDs) == 0 {
+		return nil
+	}
+
+	form := url.Values{}
+	for _, id := range workerIDs {
+		form.Add("fields[watchers][]", prefix+strconv.Itoa(id))
+	}
+
+	var r recordStatusResponse
+	err := c.performRequest(http.MethodPut, "records/"+record.Context+"/"+strconv.Itoa(record.ID)+".json", nil, form, &r)
+
+	if err != nil {
+		return fmt.Errorf("Failed to update watchers of %s: %v", record, err)
+	}
+
+	return nil
+}
+
+// ListWatchers lists the workers watching the record, in worker ID order
+func (c Cerberus) ListWatchers(record RecordRef) (*[]Worker, error) {
+	params := url.Values{}
+	params.Set("expand", "watchers")
+
+	var r struct {
+		Watchers json.RawMessage `json:"watchers"`
+	}
+	err := c.performRequest(http.MethodGet, "records/"+record.Context+"/"+strconv.Itoa(record.ID)+".json", params, nil, &r)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list watchers of %s: %v", record, err)
+	}
+
+	workers := []Worker{}
+	err = decodeExpandedRecords(r.Watchers, &workers)
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding watchers of %s: %v", record, err)
+	}
+
+	return &workers, nil
+}
@@ -0,0 +1,272 @@
+package cerb
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned by lookups when Cerb has no record matching the request.
+var ErrNotFound = errors.New("record not found")
+
+// Worker represents a Cerb worker (an agent that can be assigned tickets). @see https://cerb.ai/docs/records/types/worker/
+type Worker struct {
+	ID          int    `json:"id"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	FullName    string `json:"full_name"`
+	Email       string `json:"email_address"`
+	Title       string `json:"title"`
+	MentionName string `json:"at_mention_name"`
+	Timezone    string `json:"timezone"`
+	Language    string `json:"language"`
+	IsDisabled  int    `json:"is_disabled"`
+	IsSuperuser int    `json:"is_superuser"`
+	CalendarID  int    `json:"calendar_id"`
+	URL         string `json:"record_url"`
+	Updated     int    `json:"updated"`
+}
+
+// SearchWorkersResponse is the response from the records/worker/search.json endpoint
+type SearchWorkersResponse struct {
+	Status  string   `json:"__status"`
+	Count   int      `json:"count"`
+	Limit   int      `json:"limit"`
+	Page    int      `json:"page"`
+	Results []Worker `json:"results"`
+	Total   int      `json:"total"`
+	Version string   `json:"__version"`
+}
+
+// WorkerGroupMembership describes a worker's role within a group
+type WorkerGroupMembership struct {
+	Group     Group
+	IsManager bool
+}
+
+// SearchWorkers finds workers matching the given Cerb search query, e.g. `isDisabled:n`, following Cerb's pagination. An empty query returns every worker.
+func (c Cerberus) SearchWorkers(query string, opts ...SearchOptions) (*[]Worker, error) {
+	limit := 250 // Maximum of 250 enforced by server
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(limit))
+	applySearchOptions(params, opts)
+
+	workers := []Worker{}
+	for page := 0; ; page++ {
+		params.Set("page", strconv.Itoa(page))
+
+		var r SearchWorkersResponse
+		err := c.performRequest(http.MethodGet, "records/worker/search.json", params, nil, &r)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to search workers on page %d: %v", page, err)
+		}
+
+		workers = append(workers, r.Results...)
+		if len(r.Results) == 0 || len(workers) >= r.Total {
+			break
+		}
+	}
+
+	return &workers, nil
+}
+
+// FindAllWorkers lists every worker, including disabled ones
+func (c Cerberus) FindAllWorkers(opts ...SearchOptions) (*[]Worker, error) {
+	return c.SearchWorkers("", opts...)
+}
+
+// GetWorker loads the worker with the given ID
+func (c Cerberus) GetWorker(workerID int) (*Worker, error) {
+	var w Worker
+	err := c.performRequest(http.MethodGet, "records/worker/"+strconv.Itoa(workerID)+".json", nil, nil, &w)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get worker %d: %v", workerID, err)
+	}
+
+	return &w, nil
+}
+
+// FindWorkerByEmail finds the worker with the given email address. Returns ErrNotFound when there is no such worker.
+func (c Cerberus) FindWorkerByEmail(email string) (*Worker, error) {
+	workers, err := c.SearchWorkers(`email:"` + email + `"`)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, w := range *workers {
+		if strings.EqualFold(w.Email, email) {
+			return &w, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// FindWorkerGroups lists the groups the given worker belongs to and whether they manage each one
+func (c Cerberus) FindWorkerGroups(workerID int, opts ...SearchOptions) (*[]WorkerGroupMembership, error) {
+	limit := 250 // If you need pagination imitate ListOpenTickets
+	params := url.Values{}
+	params.Set("q", "member:(id:"+strconv.Itoa(workerID)+")")
+	params.Set("limit", strconv.Itoa(limit))
+	applySearchOptions(params, opts)
+
+	var members SearchGroupResponse
+	err := c.performRequest(http.MethodGet, "records/group/search.json", params, nil, &members)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to search groups for worker %d: %v", workerID, err)
+	}
+
+	params.Set("q", "manager:(id:"+strconv.Itoa(workerID)+")")
+	applySearchOptions(params, opts)
+
+	var managers SearchGroupResponse
+	err = c.performRequest(http.MethodGet, "records/group/search.json", params, nil, &managers)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to search managed groups for worker %d: %v", workerID, err)
+	}
+
+	managed := map[int]bool{}
+	for _, g := range managers.Results {
+		managed[g.ID] = true
+	}
+
+	memberships := make([]WorkerGroupMembership, len(members.Results))
+	for i, g := range members.Results {
+		memberships[i] = WorkerGroupMembership{Group: g, IsManager: managed[g.ID]}
+	}
+
+	return &memberships, nil
+}
+
+// IsWorkerAvailable checks the worker's availability calendar to see if they're available right now
+func (c Cerberus) IsWorkerAvailable(workerID int) (bool, error) {
+	workers, err := c.SearchWorkers(`id:` + strconv.Itoa(workerID) + ` isAvailable:"now"`)
+
+	if err != nil {
+		return false, fmt.Errorf("Failed to check availability of worker %d: %v", workerID, err)
+	}
+
+	return len(*workers) > 0, nil
+}
+
+// workerIndex is the cached form of the worker list
+type workerIndex struct {
+	workers []Worker
+	byID    map[int]int
+	byEmail map[string]int
+}
+
+func (c Cerberus) cachedWorkerIndex() (*workerIndex, error) {
+	v, err := c.cached(CacheKeyWorkers, func() (interface{}, error) {
+		workers, err := c.FindAllWorkers()
+		if err != nil {
+			return nil, err
+		}
+
+		idx := &workerIndex{
+			workers: *workers,
+			byID:    make(map[int]int, len(*workers)),
+			byEmail: make(map[string]int, len(*workers)),
+		}
+		for i, w := range *workers {
+			idx.byID[w.ID] = i
+			idx.byEmail[strings.ToLower(w.Email)] = i
+		}
+		return idx, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*workerIndex), nil
+}
+
+// CachedWorkers is FindAllWorkers served from the client's cache. The returned slice is shared so don't modify it.
+func (c Cerberus) CachedWorkers() ([]Worker, error) {
+	idx, err := c.cachedWorkerIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	return idx.workers, nil
+}
+
+// CachedWorker is GetWorker served from the client's cache. Returns ErrNotFound when there is no such worker.
+func (c Cerberus) CachedWorker(workerID int) (*Worker, error) {
+	idx, err := c.cachedWorkerIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	i, ok := idx.byID[workerID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	w := idx.workers[i]
+	return &w, nil
+}
+
+// CachedWorkerByEmail is FindWorkerByEmail served from the client's cache. Returns ErrNotFound when there is no such worker.
+func (c Cerberus) CachedWorkerByEmail(email string) (*Worker, error) {
+	idx, err := c.cachedWorkerIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	i, ok := idx.byEmail[strings.ToLower(email)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	w := idx.workers[i]
+	return &w, nil
+}
+
+// WorkerDirectory caches worker lookups for the given TTL so bulk jobs that repeatedly resolve the same workers don't hit the API each time. It keeps its own cache, independent of the client's, and is safe for concurrent use.
+type WorkerDirectory struct {
+	c Cerberus
+}
+
+// NewWorkerDirectory creates a WorkerDirectory that refreshes its list of workers once it is older than ttl
+func NewWorkerDirectory(c Cerberus, ttl time.Duration) *WorkerDirectory {
+	return &WorkerDirectory{
+		c: c.WithCache(NewLRUCache(defaultCacheSize), ttl),
+	}
+}
+
+// All returns every worker
+func (d *WorkerDirectory) All() ([]Worker, error) {
+	workers, err := d.c.CachedWorkers()
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]Worker(nil), workers...), nil
+}
+
+// Get returns the worker with the given ID. Returns ErrNotFound when there is no such worker.
+func (d *WorkerDirectory) Get(workerID int) (*Worker, error) {
+	return d.c.CachedWorker(workerID)
+}
+
+// GetByEmail returns the worker with the given email address. Returns ErrNotFound when there is no such worker.
+func (d *WorkerDirectory) GetByEmail(email string) (*Worker, error) {
+	return d.c.CachedWorkerByEmail(email)
+}
+
+// Invalidate forces the next lookup to reload workers from Cerb
+func (d *WorkerDirectory) Invalidate() {
+	d.c.InvalidateCache(CacheKeyWorkers)
+}
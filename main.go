@@ -15,9 +15,11 @@ import (
 )
 
 var cerbCredsFilepath string
+var cerbDestination string
 
 func main() {
 	flag.StringVar(&cerbCredsFilepath, "cerb-creds", "~/.config/cerb/creds.json", "Path to file containing Cerb credentials.")
+	flag.StringVar(&cerbDestination, "cerb-destination", "", "Group/Bucket to create the test ticket in. Uses the hardcoded group and bucket ids when empty.")
 	flag.Parse()
 
 	client := &http.Client{}
@@ -40,6 +42,7 @@ func testCreateTicket(c cerb.Cerberus) {
 	q := cerb.CustomerQuestion{
 		BucketID:     1049,
 		GroupID:      900,
+		Destination:  cerbDestination,
 		Content:      "Hello there! ❤️",
 		From:         "dave+gocerb@1password.com",
 		Notes:        "Some exciting notes that stand out in a stunning yellow. 🎨",
@@ -0,0 +1,329 @@
+// Package webhook receives events POSTed by Cerb automations.
+//
+// Configure an automation (e.g. on "Record changed" for tickets) with an http.request action that POSTs a JSON payload to your service:
+//
+//	{
+//		"id": "{{ record_id }}-{{ record_updated }}-ticket.status_changed",
+//		"event": "ticket.status_changed",
+//		"timestamp": {{ 'now'|date('U') }},
+//		"previous_status": "{{ was_record_status }}",
+//		"ticket": { "id": {{ record_id }}, "mask": "{{ record_mask }}", ... }
+//	}
+//
+// The id must be derived from the change rather than random, so every delivery of the same change, including one from the automation running again, carries the same ID and is only handled once.
+//
+// Send an X-Cerb-Signature header holding the hex encoded HMAC-SHA256 of the body using a secret shared with your service. Handler validates the signature, decodes the payload into an Event and dispatches it to the callbacks registered for its type.
+package webhook
+
+import (
+	"container/list"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dteare/gocerb/cerb"
+)
+
+// SignatureHeader is the request header holding the hex encoded HMAC-SHA256 signature of the body
+const SignatureHeader = "X-Cerb-Signature"
+
+// maxBodySize limits how much of a request body is read. Payloads are small so anything larger is rejected.
+const maxBodySize = 1 << 20
+
+// seenEventsSize is how many event IDs are remembered to reject replays
+const seenEventsSize = 10000
+
+// inFlightRetryAfter is the Retry-After, in seconds, sent with a delivery of an event that is still being handled
+const inFlightRetryAfter = "10"
+
+// DefaultMaxAge is how far an event's timestamp may be from the current time before Handler rejects it. Replayed requests older than this are rejected even after a restart, when the remembered event IDs are lost.
+const DefaultMaxAge = 5 * time.Minute
+
+// EventType identifies what happened in Cerb
+type EventType string
+
+// Event types understood by Handler. Automations may send other types; register callbacks for them with On the same way.
+const (
+	TicketCreated       EventType = "ticket.created"
+	TicketStatusChanged EventType = "ticket.status_changed"
+	MessageReceived     EventType = "message.received"
+	CommentAdded        EventType = "comment.added"
+)
+
+// Message is the message included with MessageReceived events
+type Message struct {
+	ID          int    `json:"id"`
+	TicketID    int    `json:"ticket_id"`
+	SenderEmail string `json:"sender_email"`
+	Content     string `json:"content"`
+	IsOutgoing  int    `json:"is_outgoing"`
+	Created     int    `json:"created"`
+}
+
+// Comment is the comment included with CommentAdded events
+type Comment struct {
+	ID            int    `json:"id"`
+	Comment       string `json:"comment"`
+	AuthorContext string `json:"author__context"`
+	AuthorID      int    `json:"author_id"`
+	TargetContext string `json:"target__context"`
+	TargetID      int    `json:"target_id"`
+	Created       int    `json:"created"`
+}
+
+// Event is a decoded webhook payload. Only the records relevant to its Type are set.
+type Event struct {
+	ID             string               `json:"id"` // Unique per event; used to ignore replays
+	Type           EventType            `json:"event"`
+	Timestamp      int64                `json:"timestamp"`
+	Ticket         *cerb.CerberusTicket `json:"ticket"`
+	PreviousStatus string               `json:"previous_status"` // Set for TicketStatusChanged
+	Message        *Message             `json:"message"`         // Set for MessageReceived
+	Comment        *Comment             `json:"comment"`         // Set for CommentAdded
+
+	Raw json.RawMessage `json:"-"` // The full payload, for fields this package doesn't model
+}
+
+// Func handles an event. Returning an error makes Handler retry it. When a delivery fails, a redelivery of the event only runs the callbacks that haven't succeeded yet, as long as the handler is still running and remembers the event.
+type Func func(e Event) error
+
+// Handler is an http.Handler that validates, decodes and dispatches Cerb webhook events. It is safe for concurrent use.
+type Handler struct {
+	secret []byte
+
+	mu        sync.RWMutex
+	callbacks map[EventType][]Func
+	retries   int
+	backoff   time.Duration
+	maxAge    time.Duration
+
+	seen *seenEvents
+}
+
+// NewHandler creates a Handler that only accepts requests signed with the shared secret and timestamped within DefaultMaxAge. Failed callbacks are retried twice, a second apart, by default.
+func NewHandler(secret string) *Handler {
+	return &Handler{
+		secret:    []byte(secret),
+		callbacks: map[EventType][]Func{},
+		retries:   2,
+		backoff:   time.Second,
+		maxAge:    DefaultMaxAge,
+		seen:      newSeenEvents(seenEventsSize),
+	}
+}
+
+// On registers fn to be called for events of the given type. Callbacks run in the order they were registered.
+func (h *Handler) On(t EventType, fn Func) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.callbacks[t] = append(h.callbacks[t], fn)
+}
+
+// SetRetries controls how many times a failing callback is retried, waiting backoff before the first retry and doubling it each time after
+func (h *Handler) SetRetries(retries int, backoff time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.retries = retries
+	h.backoff = backoff
+}
+
+// SetMaxAge controls how far an event's timestamp may be from the current time, in either direction, before it's rejected, so redeliveries of a failed event must also arrive within it. Keep it well under the time it takes to receive seenEventsSize events so replays within the window are still caught by their ID. Zero disables the check.
+func (h *Handler) SetMaxAge(maxAge time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.maxAge = maxAge
+}
+
+// Sign returns the signature Cerb should send in SignatureHeader for the given body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (h *Handler) validSignature(body []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// ServeHTTP responds 200 once every callback for the event succeeds (or the event was already handled), 401 for a bad signature, 400 for a malformed or expired payload and 500 when a callback keeps failing so Cerb can retry the delivery. A delivery of an event that another delivery is still handling gets 503 with a Retry-After header, as its outcome isn't known yet.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, "Failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	if !h.validSignature(body, r.Header.Get(SignatureHeader)) {
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var e Event
+	err = json.Unmarshal(body, &e)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid payload: %v", err), http.StatusBadRequest)
+		return
+	}
+	e.Raw = body
+
+	if e.ID == "" || e.Type == "" {
+		http.Error(w, "Payload is missing its id or event", http.StatusBadRequest)
+		return
+	}
+
+	h.mu.RLock()
+	maxAge := h.maxAge
+	h.mu.RUnlock()
+
+	if maxAge > 0 {
+		age := time.Since(time.Unix(e.Timestamp, 0))
+		if age > maxAge || age < -maxAge {
+			http.Error(w, "Event timestamp is outside the allowed window", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Reserve the ID before dispatching so concurrent deliveries of the same event only run once
+	start, state, ok := h.seen.reserve(e.ID)
+	if !ok {
+		if state == eventRunning {
+			w.Header().Set("Retry-After", inFlightRetryAfter)
+			http.Error(w, "Event is already being handled", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	done, err := h.dispatch(e, start)
+	h.seen.finish(e.ID, done, err == nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// dispatch runs the callbacks for the event from start onwards, returning how many have succeeded in total
+func (h *Handler) dispatch(e Event, start int) (int, error) {
+	h.mu.RLock()
+	callbacks := h.callbacks[e.Type]
+	retries := h.retries
+	backoff := h.backoff
+	h.mu.RUnlock()
+
+	for i := start; i < len(callbacks); i++ {
+		fn := callbacks[i]
+		wait := backoff
+		err := fn(e)
+
+		for attempt := 0; err != nil && attempt < retries; attempt++ {
+			time.Sleep(wait)
+			wait *= 2
+			err = fn(e)
+		}
+
+		if err != nil {
+			return i, fmt.Errorf("Callback %d for %s event %s failed after %d attempts: %v", i, e.Type, e.ID, retries+1, err)
+		}
+	}
+
+	return len(callbacks), nil
+}
+
+// seenEvents remembers the most recent event IDs so replayed deliveries are ignored, along with how far failed events got so redeliveries pick up where they left off
+type seenEvents struct {
+	max int
+
+	mu    sync.Mutex
+	ids   map[string]*list.Element
+	order *list.List // Front is the most recent
+}
+
+type seenEventState int
+
+const (
+	eventRunning seenEventState = iota
+	eventFailed
+	eventHandled
+)
+
+type seenEvent struct {
+	id    string
+	state seenEventState
+	done  int // Callbacks that succeeded
+}
+
+func newSeenEvents(max int) *seenEvents {
+	return &seenEvents{
+		max:   max,
+		ids:   map[string]*list.Element{},
+		order: list.New(),
+	}
+}
+
+// reserve records the ID, returning false along with the event's state if it was already handled or is being handled. A previously failed event is reserved again along with the index of the first callback still to run.
+func (s *seenEvents) reserve(id string) (int, seenEventState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.ids[id]; ok {
+		e := el.Value.(*seenEvent)
+		if e.state != eventFailed {
+			return 0, e.state, false
+		}
+
+		e.state = eventRunning
+		s.order.MoveToFront(el)
+		return e.done, eventRunning, true
+	}
+
+	s.ids[id] = s.order.PushFront(&seenEvent{id: id})
+	if s.order.Len() > s.max {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.ids, oldest.Value.(*seenEvent).id)
+	}
+
+	return 0, eventRunning, true
+}
+
+// finish records the outcome of dispatching the event
+func (s *seenEvents) finish(id string, done int, handled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.ids[id]
+	if !ok {
+		return
+	}
+
+	e := el.Value.(*seenEvent)
+	e.done = done
+	e.state = eventFailed
+	if handled {
+		e.state = eventHandled
+	}
+}
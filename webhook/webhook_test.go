@@ -0,0 +1,194 @@
+package webhook
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testSecret = "s3cret"
+
+func payload(id string, timestamp time.Time) []byte {
+	return []byte(`{"id":"` + id + `","event":"ticket.created","timestamp":` + strconv.FormatInt(timestamp.Unix(), 10) + `,"ticket":{"id":1}}`)
+}
+
+func post(t *testing.T, url string, body []byte, signature string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signature != "" {
+		req.Header.Set(SignatureHeader, signature)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp
+}
+
+func TestHandlerRejectsInvalidRequests(t *testing.T) {
+	h := NewHandler(testSecret)
+	calls := 0
+	h.On(TicketCreated, func(e Event) error {
+		calls++
+		return nil
+	})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	now := time.Now()
+	tests := []struct {
+		name      string
+		body      []byte
+		signature string
+		want      int
+	}{
+		{"missing signature", payload("a", now), "", http.StatusUnauthorized},
+		{"wrong secret", payload("a", now), Sign("other", payload("a", now)), http.StatusUnauthorized},
+		{"signature of another body", payload("a", now), Sign(testSecret, payload("b", now)), http.StatusUnauthorized},
+		{"not hex", payload("a", now), "zz", http.StatusUnauthorized},
+		{"stale timestamp", payload("a", now.Add(-DefaultMaxAge-time.Minute)), Sign(testSecret, payload("a", now.Add(-DefaultMaxAge-time.Minute))), http.StatusBadRequest},
+		{"future timestamp", payload("a", now.Add(DefaultMaxAge+time.Minute)), Sign(testSecret, payload("a", now.Add(DefaultMaxAge+time.Minute))), http.StatusBadRequest},
+		{"malformed payload", []byte(`{`), Sign(testSecret, []byte(`{`)), http.StatusBadRequest},
+		{"missing id", []byte(`{"event":"ticket.created"}`), Sign(testSecret, []byte(`{"event":"ticket.created"}`)), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := post(t, srv.URL, tt.body, tt.signature)
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+
+	if calls != 0 {
+		t.Errorf("callback ran %d times for rejected requests", calls)
+	}
+}
+
+func TestHandlerIgnoresReplays(t *testing.T) {
+	h := NewHandler(testSecret)
+	calls := 0
+	h.On(TicketCreated, func(e Event) error {
+		calls++
+		return nil
+	})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	body := payload("replayed", time.Now())
+	for i := 0; i < 3; i++ {
+		if resp := post(t, srv.URL, body, Sign(testSecret, body)); resp.StatusCode != http.StatusOK {
+			t.Fatalf("delivery %d status = %d, want %d", i, resp.StatusCode, http.StatusOK)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("callback ran %d times, want 1", calls)
+	}
+}
+
+func TestHandlerConcurrentDuplicate(t *testing.T) {
+	h := NewHandler(testSecret)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	h.On(TicketCreated, func(e Event) error {
+		close(started)
+		<-release
+		return nil
+	})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	body := payload("concurrent", time.Now())
+	var wg sync.WaitGroup
+	first := 0
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		req, _ := http.NewRequest(http.MethodPost, srv.URL, bytes.NewReader(body))
+		req.Header.Set(SignatureHeader, Sign(testSecret, body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		resp.Body.Close()
+		first = resp.StatusCode
+	}()
+	<-started
+
+	resp := post(t, srv.URL, body, Sign(testSecret, body))
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("duplicate status = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+	if resp.Header.Get("Retry-After") == "" {
+		t.Error("duplicate response has no Retry-After header")
+	}
+
+	close(release)
+	wg.Wait()
+	if first != http.StatusOK {
+		t.Errorf("first delivery status = %d, want %d", first, http.StatusOK)
+	}
+
+	if resp := post(t, srv.URL, body, Sign(testSecret, body)); resp.StatusCode != http.StatusOK {
+		t.Errorf("delivery after handling status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func TestHandlerRetryResumesFromFailedCallback(t *testing.T) {
+	h := NewHandler(testSecret)
+	h.SetRetries(0, 0)
+
+	var calls []string
+	failing := true
+	h.On(TicketCreated, func(e Event) error {
+		calls = append(calls, "first")
+		return nil
+	})
+	h.On(TicketCreated, func(e Event) error {
+		calls = append(calls, "second")
+		if failing {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+	h.On(TicketCreated, func(e Event) error {
+		calls = append(calls, "third")
+		return nil
+	})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	body := payload("retried", time.Now())
+	if resp := post(t, srv.URL, body, Sign(testSecret, body)); resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("failing delivery status = %d, want %d", resp.StatusCode, http.StatusInternalServerError)
+	}
+
+	failing = false
+	if resp := post(t, srv.URL, body, Sign(testSecret, body)); resp.StatusCode != http.StatusOK {
+		t.Fatalf("redelivery status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	want := []string{"first", "second", "second", "third"}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Fatalf("calls = %v, want %v", calls, want)
+		}
+	}
+}